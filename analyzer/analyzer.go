@@ -4,16 +4,52 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"slices"
+	"sort"
+	"strconv"
 	"strings"
 
 	"ajpa/analyzer/internal"
 )
 
+// AnalyzeOptions are the options used when analyzing the project.
+type AnalyzeOptions struct {
+	// SkipMalformedConfig skips the config files which can not be read or parsed, and records them as warnings in
+	// ProjectAnalysisResult.Diagnostics, instead of failing the analysis.
+	SkipMalformedConfig bool
+	// ConfigRepositoryPath is the local directory or git checkout used by Spring Cloud Config Server. If set, the
+	// properties served by the config server for each application are merged into the application's properties.
+	ConfigRepositoryPath string
+	// ReadDeploymentConfig reads .env files and Kubernetes manifests in k8s/*.yaml, in the project root and in each
+	// application's directory, as the lowest precedence property source.
+	ReadDeploymentConfig bool
+	// DetectionRulesPath is a YAML file of rules detecting backing services, in the same format as
+	// detection_rules.yaml. Its rules replace the embedded rules having the same id, or are added after them. The
+	// file is read once before analyzing the modules, and an invalid file fails the analysis.
+	DetectionRulesPath string
+}
+
 func AnalyzeJavaProject(projectRootPath string) (ProjectAnalysisResult, error) {
-	return analyzeJavaProjectSubDirectory(projectRootPath, projectRootPath)
+	return AnalyzeJavaProjectWithOptions(projectRootPath, AnalyzeOptions{})
+}
+
+func AnalyzeJavaProjectWithOptions(projectRootPath string, options AnalyzeOptions) (ProjectAnalysisResult, error) {
+	rules, err := loadDetectionRules(options.DetectionRulesPath)
+	if err != nil {
+		return ProjectAnalysisResult{}, err
+	}
+	result, err := analyzeJavaProjectSubDirectory(projectRootPath, projectRootPath, options, rules)
+	if err != nil {
+		return ProjectAnalysisResult{}, err
+	}
+	if err = resolveApplicationTopology(&result); err != nil {
+		return ProjectAnalysisResult{}, err
+	}
+	return result, nil
 }
 
-func analyzeJavaProjectSubDirectory(projectRootPath string, subDirectoryPath string) (ProjectAnalysisResult, error) {
+func analyzeJavaProjectSubDirectory(projectRootPath string, subDirectoryPath string, options AnalyzeOptions,
+	rules []detectionRule) (ProjectAnalysisResult, error) {
 	entries, err := os.ReadDir(subDirectoryPath)
 	if err != nil {
 		return ProjectAnalysisResult{}, fmt.Errorf("reading directory: %w", err)
@@ -24,7 +60,7 @@ func analyzeJavaProjectSubDirectory(projectRootPath string, subDirectoryPath str
 	for _, entry := range entries {
 		if entry.IsDir() {
 			newResult, err := analyzeJavaProjectSubDirectory(projectRootPath,
-				filepath.Join(subDirectoryPath, entry.Name()))
+				filepath.Join(subDirectoryPath, entry.Name()), options, rules)
 			if err != nil {
 				return ProjectAnalysisResult{}, fmt.Errorf("analyzing java project: %w", err)
 			}
@@ -33,214 +69,542 @@ func analyzeJavaProjectSubDirectory(projectRootPath string, subDirectoryPath str
 				return ProjectAnalysisResult{}, err
 			}
 		} else {
-			// todo:
-			// 1. Support file names like backend-pom.xml
-			// 2. Support build.gradle
-			if strings.ToLower(entry.Name()) == "pom.xml" {
-				pomPath := filepath.Join(subDirectoryPath, entry.Name())
-				newResult, err := analyzePomProject(projectRootPath, pomPath)
-				if err != nil {
-					return ProjectAnalysisResult{}, err
-				}
-				// todo: consider multiple pom use same Azure resource
-				result, err = mergeProjectAnalysisResult(result, newResult)
-				if err != nil {
-					return ProjectAnalysisResult{}, err
+			// todo: Support file names like backend-pom.xml
+			var newResult ProjectAnalysisResult
+			var err error
+			switch entry.Name() {
+			case "pom.xml":
+				newResult, err = analyzePomProject(projectRootPath, filepath.Join(subDirectoryPath, entry.Name()),
+					options, rules)
+			case "build.gradle", "build.gradle.kts":
+				// Projects having both pom.xml and Gradle build files are analyzed by pom.xml.
+				if _, statErr := os.Stat(filepath.Join(subDirectoryPath, "pom.xml")); statErr == nil {
+					continue
 				}
+				newResult, err = analyzeGradleProject(projectRootPath, filepath.Join(subDirectoryPath, entry.Name()),
+					options, rules)
+			default:
+				continue
+			}
+			if err != nil {
+				return ProjectAnalysisResult{}, err
+			}
+			// todo: consider multiple pom use same Azure resource
+			result, err = mergeProjectAnalysisResult(result, newResult)
+			if err != nil {
+				return ProjectAnalysisResult{}, err
 			}
 		}
 	}
 	return result, nil
 }
 
-func analyzePomProject(projectRootPath string, pomFileAbsolutePath string) (ProjectAnalysisResult, error) {
+func analyzePomProject(projectRootPath string, pomFileAbsolutePath string, options AnalyzeOptions,
+	rules []detectionRule) (ProjectAnalysisResult, error) {
 	pom, err := internal.CreateEffectivePom(pomFileAbsolutePath)
 	if err != nil {
 		return ProjectAnalysisResult{}, fmt.Errorf("creating effective pom: %w", err)
 	}
-	pomRelativePathPath, err := filepath.Rel(projectRootPath, pomFileAbsolutePath)
+	return analyzeProject(projectRootPath, pomFileAbsolutePath, pom, options, rules)
+}
+
+// analyzeGradleProject analyzes the Gradle project by the plugins and dependencies declared in the build file, see
+// internal.ReadGradleBuildModel.
+func analyzeGradleProject(projectRootPath string, buildFileAbsolutePath string, options AnalyzeOptions,
+	rules []detectionRule) (ProjectAnalysisResult, error) {
+	pom, ok := internal.ReadGradleBuildModel(filepath.Dir(buildFileAbsolutePath))
+	if !ok {
+		return ProjectAnalysisResult{}, nil
+	}
+	return analyzeProject(projectRootPath, buildFileAbsolutePath, pom, options, rules)
+}
+
+func analyzeProject(projectRootPath string, buildFileAbsolutePath string, pom internal.Pom,
+	options AnalyzeOptions, rules []detectionRule) (ProjectAnalysisResult, error) {
+	buildFileRelativePath, err := filepath.Rel(projectRootPath, buildFileAbsolutePath)
 	if err != nil {
 		return ProjectAnalysisResult{}, err
 	}
-	pom.PomFilePath = pomRelativePathPath
-	if !isSpringBootRunnableProject(pom) {
+	pom.PomFilePath = buildFileRelativePath
+	framework, ok := detectFramework(pom)
+	if !ok {
 		return ProjectAnalysisResult{}, nil
 	}
+	projectPath := filepath.Dir(buildFileAbsolutePath)
+	annotations, err := internal.ScanJavaAnnotations(projectPath)
+	if err != nil {
+		return ProjectAnalysisResult{}, err
+	}
 	result := ProjectAnalysisResult{}
-	projectRelativePath := filepath.Dir(pomRelativePathPath)
+	projectRelativePath := filepath.Dir(buildFileRelativePath)
 	// 1. Add Application
-	applicationName := internal.GetNameFromDirPath(filepath.Dir(pomFileAbsolutePath))
-	err = addApplicationToResult(&result, applicationName, Application{projectRelativePath})
+	applicationName := internal.GetNameFromDirPath(projectPath)
+	var deploymentConfigSearchPaths []string
+	if options.ReadDeploymentConfig {
+		deploymentConfigSearchPaths = internal.AppendAndDistinctInOrder(nil, projectRootPath, projectPath)
+	}
+	config, err := readApplicationConfig(projectPath, framework, internal.SpringBootConfigOptions{
+		ResourceRoots:               internal.GetResourceRoots(projectPath, pom),
+		FilteringProperties:         internal.GetResourceFilteringProperties(pom),
+		ConfigRepositoryPath:        options.ConfigRepositoryPath,
+		DeploymentConfigSearchPaths: deploymentConfigSearchPaths,
+		SkipMalformedFiles:          options.SkipMalformedConfig,
+	})
+	if err != nil {
+		return ProjectAnalysisResult{}, fmt.Errorf("reading config of %s: %w", applicationName, err)
+	}
+	for _, skippedFile := range config.SkippedFiles {
+		result.Diagnostics = append(result.Diagnostics, newSkippedConfigFileDiagnostic(projectRootPath,
+			applicationName, skippedFile))
+	}
+	for _, skippedTemplate := range config.SkippedTemplates {
+		result.Diagnostics = append(result.Diagnostics, newSkippedHelmTemplateDiagnostic(projectRootPath,
+			applicationName, skippedTemplate))
+	}
+	properties := config.PropertyValues
+	javaVersion := internal.GetJavaVersion(projectPath, projectRootPath, pom)
+	springBootVersion := internal.GetSpringBootVersion(projectPath, pom)
+	result.Diagnostics = append(result.Diagnostics, newVersionDiagnostics(applicationName, buildFileRelativePath,
+		javaVersion, springBootVersion)...)
+	webApplicationType := detectFrameworkWebApplicationType(framework, pom, properties, annotations)
+	port := 0
+	if webApplicationType != WebApplicationTypeNone {
+		port = getFrameworkServerPort(framework, properties)
+	}
+	err = addApplicationToResult(&result, applicationName, Application{
+		ProjectRelativePath:   projectRelativePath,
+		Framework:             framework,
+		ExternalConfigSources: config.ExternalConfigImports,
+		Properties:            newApplicationProperties(projectRootPath, config.PropertyValues),
+		WebApplicationType:    webApplicationType,
+		Port:                  port,
+		HealthProbes:          detectFrameworkHealthProbes(framework, pom, properties, webApplicationType, port),
+		SpringCloudComponents: detectSpringCloudComponents(pom, properties, config.ExternalConfigImports),
+		ServiceReferences:     getServiceReferences(properties, annotations),
+		JavaVersion:           javaVersion,
+		SpringBootVersion:     springBootVersion,
+		KeyVaultProperties:    internal.GetKeyVaultSecretProperties(properties),
+	})
 	if err != nil {
 		return result, err
 	}
 	// 2. Add Application related hosting Service
 	hostingServiceName := applicationName
-	err = addApplicationRelatedHostingServiceToResult(&result, applicationName, hostingServiceName, AzureContainerApp{})
+	var hostingService Service = AzureContainerApp{}
+	if framework == FrameworkAzureFunctions {
+		hostingService = AzureFunctionApp{}
+	}
+	err = addApplicationRelatedHostingServiceToResult(&result, applicationName, hostingServiceName, hostingService)
 	if err != nil {
 		return result, err
 	}
 	// 3. Add Application related backing Service
-	properties := internal.ReadProperties(filepath.Dir(pomFileAbsolutePath))
-	if err = detectPostgresql(&result, applicationName, pom, properties); err != nil {
+	detection := detectionContext{
+		result:          &result,
+		applicationName: applicationName,
+		projectRootPath: projectRootPath,
+		projectPath:     projectPath,
+		pom:             pom,
+		properties:      properties,
+		annotations:     annotations,
+	}
+	if err = detectDatasources(detection); err != nil {
 		return ProjectAnalysisResult{}, err
 	}
-	if err = detectMysql(&result, applicationName, pom, properties); err != nil {
+	if err = detectBackingServices(detection, rules); err != nil {
 		return ProjectAnalysisResult{}, err
 	}
-	if err = detectRedis(&result, applicationName, pom); err != nil {
+	detectOracleDatabase(detection)
+	if err = detectOpenAiModels(detection); err != nil {
 		return ProjectAnalysisResult{}, err
 	}
-	if err = detectMongo(&result, applicationName, pom); err != nil {
+	if err = detectByRegisteredDetectors(detection); err != nil {
 		return ProjectAnalysisResult{}, err
 	}
-	if err = detectCosmos(&result, applicationName, pom); err != nil {
-		return ProjectAnalysisResult{}, err
+	if framework == FrameworkAzureFunctions {
+		if err = detectFunctionsBindings(detection); err != nil {
+			return ProjectAnalysisResult{}, err
+		}
 	}
-	if err = detectServiceBus(&result, applicationName, pom, properties); err != nil {
-		return ProjectAnalysisResult{}, err
+	detectManuallyProvisionedDatabases(detection)
+	return result, nil
+}
+
+// servletDependencies are the dependencies bringing spring-boot-starter-web transitively, group id -> artifact ids.
+// The effective pom only has the declared dependencies, so they are checked besides spring-boot-starter-web.
+var servletDependencies = map[string][]string{
+	"org.springframework.boot": {"spring-boot-starter-web", "spring-boot-starter-jersey",
+		"spring-boot-starter-websocket", "spring-boot-starter-data-rest"},
+	"org.springframework.cloud": {"spring-cloud-config-server", "spring-cloud-starter-netflix-eureka-server"},
+	"de.codecentric":            {"spring-boot-admin-starter-server"},
+}
+
+// detectWebApplicationType detects the web application type like Spring Boot. spring.main.web-application-type takes
+// precedence, then servlet is used if spring-boot-starter-web or any of servletDependencies exists, then reactive is
+// used if WebFlux or Spring Cloud Gateway exists. Without these dependencies, the application is considered as a
+// servlet application if it has controllers.
+func detectWebApplicationType(pom internal.Pom, properties internal.PropertyValues,
+	annotations []internal.JavaAnnotation) WebApplicationType {
+	if value, ok := internal.GetPropertyValue(properties, "spring.main.web-application-type"); ok {
+		switch webApplicationType := WebApplicationType(strings.ToLower(strings.TrimSpace(value))); webApplicationType {
+		case WebApplicationTypeServlet, WebApplicationTypeReactive, WebApplicationTypeNone:
+			return webApplicationType
+		}
 	}
-	if err = detectEventHubs(&result, applicationName, pom, properties); err != nil {
-		return ProjectAnalysisResult{}, err
+	switch {
+	case hasAnyDependency(pom, servletDependencies):
+		return WebApplicationTypeServlet
+	case hasDependency(pom, "org.springframework.boot", "spring-boot-starter-webflux"),
+		hasDependency(pom, "org.springframework.cloud", "spring-cloud-starter-gateway"):
+		return WebApplicationTypeReactive
+	case internal.HasJavaAnnotation(annotations, "RestController"),
+		internal.HasJavaAnnotation(annotations, "Controller"):
+		return WebApplicationTypeServlet
+	default:
+		return WebApplicationTypeNone
 	}
-	if err = detectStorageAccount(&result, applicationName, pom, properties); err != nil {
-		return ProjectAnalysisResult{}, err
+}
+
+// detectHealthProbes detects the health endpoints of Spring Boot Actuator. The liveness endpoint is also used by the
+// startup probe, so the other probes don't start before the application is live.
+func detectHealthProbes(pom internal.Pom, properties internal.PropertyValues, webApplicationType WebApplicationType,
+	port int) *HealthProbes {
+	if webApplicationType == WebApplicationTypeNone ||
+		!hasDependency(pom, "org.springframework.boot", "spring-boot-starter-actuator") {
+		return nil
+	}
+	endpoints, ok := internal.GetHealthProbeEndpoints(properties, port,
+		webApplicationType == WebApplicationTypeReactive)
+	if !ok {
+		return nil
+	}
+	return &HealthProbes{
+		Port:          endpoints.Port,
+		LivenessPath:  endpoints.LivenessPath,
+		ReadinessPath: endpoints.ReadinessPath,
+		StartupPath:   endpoints.LivenessPath,
 	}
-	return result, nil
 }
 
-func isSpringBootRunnableProject(pom internal.Pom) bool {
-	if len(pom.Modules) > 0 {
-		return false
+// collectServiceBusEntities collects the queues, topics and subscriptions of Service Bus:
+//   - Spring Cloud Stream Service Bus binder: the binding destination is a topic if the binding's entity-type is topic,
+//     or if entity-type is not set and the binding has a group. The group is the subscription of the topic.
+//   - spring-cloud-azure-starter-servicebus: the entities of the clients, see internal.GetServiceBusClientEntities.
+//   - JMS: spring.jms.template.default-destination and @JmsListener destinations. They are topics if
+//     spring.jms.servicebus.pub-sub-domain or spring.jms.pub-sub-domain is true.
+//   - @JmsListener and @ServiceBusListener, see getServiceBusDestinationsInAnnotations.
+func collectServiceBusEntities(ctx detectionContext) Service {
+	pom, properties := ctx.pom, ctx.properties
+	usesJms := hasDependency(pom, "com.azure.spring", "spring-cloud-azure-starter-servicebus-jms")
+	usesBinder := hasDependency(pom, "com.azure.spring", "spring-cloud-azure-stream-binder-servicebus")
+	usesStarter := hasDependency(pom, "com.azure.spring", "spring-cloud-azure-starter-servicebus")
+	var entities serviceBusEntities
+	if usesBinder {
+		for _, binding := range internal.GetStreamBindings(properties) {
+			entityType := internal.GetServiceBusBindingEntityType(properties, binding.Name)
+			if entityType == internal.ServiceBusEntityTypeTopic || (entityType == "" && binding.Group != "") {
+				entities.addTopic(binding.Destination, binding.Group)
+			} else {
+				entities.addQueue(binding.Destination)
+			}
+		}
 	}
-	for _, dep := range pom.Build.Plugins {
-		if dep.GroupId == "org.springframework.boot" && dep.ArtifactId == "spring-boot-maven-plugin" {
-			return true
+	if usesStarter {
+		for _, entity := range internal.GetServiceBusClientEntities(properties) {
+			if entity.EntityType == internal.ServiceBusEntityTypeTopic || entity.Subscription != "" {
+				entities.addTopic(entity.Name, entity.Subscription)
+			} else {
+				entities.addQueue(entity.Name)
+			}
 		}
 	}
-	return false
+	pubSubDomain := isPropertyTrue(properties, "spring.jms.servicebus.pub-sub-domain") ||
+		isPropertyTrue(properties, "spring.jms.pub-sub-domain")
+	if value, ok := internal.GetPropertyValue(properties, "spring.jms.template.default-destination"); usesJms && ok &&
+		strings.TrimSpace(value) != "" {
+		if pubSubDomain {
+			entities.addTopic(strings.TrimSpace(value), "")
+		} else {
+			entities.addQueue(strings.TrimSpace(value))
+		}
+	}
+	getServiceBusDestinationsInAnnotations(ctx.annotations, properties, pubSubDomain, &entities)
+	return entities.toService()
 }
 
-func detectPostgresql(result *ProjectAnalysisResult, applicationName string, pom internal.Pom,
-	properties map[string]string) error {
-	if hasDependency(pom, "org.postgresql", "postgresql") ||
-		hasDependency(pom, "com.azure.spring", "spring-cloud-azure-starter-jdbc-postgresql") {
-		return addApplicationRelatedBackingServiceToResult(result, applicationName, DefaultPostgresqlServiceName,
-			AzureDatabaseForPostgresql{getDatabaseNameFromSpringDataSourceUrlProperty(properties)})
-	}
-	return nil
+// serviceBusEntities collects the queues, topics and subscriptions of Service Bus, in the order they are found.
+type serviceBusEntities struct {
+	queues        []string
+	topics        []string
+	subscriptions map[string][]string // topic name -> subscription names
 }
 
-func detectMysql(result *ProjectAnalysisResult, applicationName string, pom internal.Pom,
-	properties map[string]string) error {
-	if hasDependency(pom, "com.mysql", "mysql-connector-j") ||
-		hasDependency(pom, "com.azure.spring", "spring-cloud-azure-starter-jdbc-mysql") {
-		// todo:
-		// 1. support multiple container app use multiple mysql
-		// 2. Support multiple container app use one mysql
-		// 3. Same to other resources like postgresql
-		return addApplicationRelatedBackingServiceToResult(result, applicationName, DefaultMysqlServiceName,
-			AzureDatabaseForMysql{getDatabaseNameFromSpringDataSourceUrlProperty(properties)})
-	}
-	return nil
+func (e *serviceBusEntities) addQueue(queue string) {
+	e.queues = internal.AppendAndDistinctInOrder(e.queues, queue)
 }
 
-func detectRedis(result *ProjectAnalysisResult, applicationName string, pom internal.Pom) error {
-	if hasDependency(pom, "org.springframework.boot", "spring-boot-starter-data-redis") ||
-		hasDependency(pom, "org.springframework.boot", "spring-boot-starter-data-redis-reactive") {
-		return addApplicationRelatedBackingServiceToResult(result, applicationName, DefaultRedisServiceName,
-			AzureCacheForRedis{})
+// addTopic adds the topic, and the subscription if it's not empty.
+func (e *serviceBusEntities) addTopic(topic string, subscription string) {
+	e.topics = internal.AppendAndDistinctInOrder(e.topics, topic)
+	if subscription == "" {
+		return
+	}
+	if e.subscriptions == nil {
+		e.subscriptions = make(map[string][]string)
 	}
-	return nil
+	e.subscriptions[topic] = internal.AppendAndDistinctInOrder(e.subscriptions[topic], subscription)
 }
 
-func detectMongo(result *ProjectAnalysisResult, applicationName string, pom internal.Pom) error {
-	if hasDependency(pom, "org.springframework.boot", "spring-boot-starter-data-mongodb") ||
-		hasDependency(pom, "org.springframework.boot", "spring-boot-starter-data-mongodb-reactive") {
-		return addApplicationRelatedBackingServiceToResult(result, applicationName, DefaultMongoServiceName,
-			AzureCosmosDbForMongoDb{})
-	}
-	return nil
+func (e *serviceBusEntities) toService() AzureServiceBus {
+	return AzureServiceBus{Queues: e.queues, Topics: e.topics, Subscriptions: e.subscriptions}
 }
 
-func detectCosmos(result *ProjectAnalysisResult, applicationName string, pom internal.Pom) error {
-	if hasDependency(pom, "com.azure.spring", "spring-cloud-azure-starter-data-cosmos") {
-		return addApplicationRelatedBackingServiceToResult(result, applicationName, DefaultCosmosServiceName,
-			AzureCosmosDb{})
+// getServiceBusDestinationsInAnnotations gets the queues, topics and subscriptions in @JmsListener and
+// @ServiceBusListener. The destination of @JmsListener is a topic if "subscription" is set, which is also the
+// subscription name, or if the container factory name contains "topic". If the container factory name contains
+// "queue", the destination is a queue. Otherwise, it's a topic if pubSubDomain is true. The destination of
+// @ServiceBusListener is a topic if "group" is set, which is the subscription name.
+func getServiceBusDestinationsInAnnotations(annotations []internal.JavaAnnotation, properties internal.PropertyValues,
+	pubSubDomain bool, entities *serviceBusEntities) {
+	for _, annotation := range annotations {
+		var isTopic bool
+		var subscriptions []string
+		switch annotation.Name {
+		case "JmsListener":
+			subscriptions = resolveAnnotationValues(annotation.Attributes["subscription"], properties)
+			isTopic = len(annotation.Attributes["subscription"]) > 0 || pubSubDomain
+			for _, containerFactory := range annotation.Attributes["containerFactory"] {
+				containerFactory = strings.ToLower(containerFactory)
+				if strings.Contains(containerFactory, "topic") {
+					isTopic = true
+				} else if strings.Contains(containerFactory, "queue") && len(subscriptions) == 0 {
+					isTopic = false
+				}
+			}
+		case "ServiceBusListener":
+			subscriptions = resolveAnnotationValues(annotation.Attributes["group"], properties)
+			isTopic = len(annotation.Attributes["group"]) > 0
+		default:
+			continue
+		}
+		for _, destination := range resolveAnnotationValues(annotation.Attributes["destination"], properties) {
+			if !isTopic {
+				entities.addQueue(destination)
+				continue
+			}
+			entities.addTopic(destination, "")
+			for _, subscription := range subscriptions {
+				entities.addTopic(destination, subscription)
+			}
+		}
 	}
-	return nil
 }
 
-func detectServiceBus(result *ProjectAnalysisResult, applicationName string, pom internal.Pom,
-	properties map[string]string) error {
-	if !hasDependency(pom, "com.azure.spring", "spring-cloud-azure-starter-servicebus-jms") &&
-		!hasDependency(pom, "com.azure.spring", "spring-cloud-azure-stream-binder-servicebus") {
-		return nil
+// collectEventHubsEntities collects the hubs and consumer groups used by the Spring Cloud Azure Event Hubs libraries,
+// and by Kafka clients which connect to the Kafka endpoint of Event Hubs, see usesKafkaOnEventHubs.
+func collectEventHubsEntities(ctx detectionContext) Service {
+	pom, properties, annotations := ctx.pom, ctx.properties, ctx.annotations
+	usesKafka, _ := usesKafkaOnEventHubs(pom, properties)
+	var entities eventHubsEntities
+	if hasDependency(pom, "com.azure.spring", "spring-cloud-azure-stream-binder-eventhubs") ||
+		(usesKafka && hasKafkaBinderDependency(pom)) {
+		for _, binding := range internal.GetStreamBindings(properties) {
+			entities.addHub(binding.Destination, binding.Group)
+		}
 	}
-	var queues []string
-	if hasDependency(pom, "com.azure.spring", "spring-cloud-azure-stream-binder-servicebus") {
-		queues = internal.AppendAndDistinct(queues, internal.GetDistinctBindingDestinationValues(properties))
+	if hasDependency(pom, "com.azure.spring", "spring-cloud-azure-starter-eventhubs") {
+		prefix := "spring.cloud.azure.eventhubs."
+		for _, client := range []string{"", "producer.", "consumer.", "processor."} {
+			hub := internal.GetFirstPropertyValue(properties, prefix+client+"event-hub-name", prefix+"event-hub-name")
+			var consumerGroup string
+			if client == "consumer." || client == "processor." {
+				consumerGroup = internal.GetFirstPropertyValue(properties, prefix+client+"consumer-group")
+			}
+			if hub != "" {
+				entities.addHub(hub, consumerGroup)
+			}
+		}
+	}
+	for _, annotation := range annotations {
+		if annotation.Name == "EventHubsListener" {
+			entities.addHubs(resolveAnnotationValues(annotation.Attributes["destination"], properties),
+				resolveAnnotationValues(annotation.Attributes["group"], properties))
+		}
+	}
+	if usesKafka {
+		for _, topic := range internal.GetKafkaTopics(properties) {
+			entities.addHub(topic, "")
+		}
+		// Same as Spring Kafka, spring.kafka.consumer.group-id is used by listeners without groupId.
+		var defaultGroupIds []string
+		if groupId := internal.GetFirstPropertyValue(properties, "spring.kafka.consumer.group-id"); groupId != "" {
+			defaultGroupIds = []string{groupId}
+		}
+		for _, annotation := range annotations {
+			if annotation.Name != "KafkaListener" {
+				continue
+			}
+			groupIds := defaultGroupIds
+			if len(annotation.Attributes["groupId"]) > 0 {
+				groupIds = resolveAnnotationValues(annotation.Attributes["groupId"], properties)
+			}
+			entities.addHubs(resolveAnnotationValues(annotation.Attributes["topics"], properties), groupIds)
+		}
 	}
-	return addApplicationRelatedBackingServiceToResult(result, applicationName, DefaultServiceBusServiceName,
-		AzureServiceBus{Queues: queues})
+	return entities.toService()
 }
 
-func detectEventHubs(result *ProjectAnalysisResult, applicationName string, pom internal.Pom,
-	properties map[string]string) error {
-	if !hasDependency(pom, "com.azure.spring", "spring-cloud-azure-stream-binder-eventhubs") &&
-		!hasDependency(pom, "com.azure.spring", "spring-cloud-azure-starter-eventhubs") &&
-		!hasDependency(pom, "com.azure.spring", "spring-cloud-azure-starter-integration-eventhubs") &&
-		!hasDependency(pom, "com.azure.spring", "spring-messaging-azure-eventhubs") &&
-		!hasDependency(pom, "com.azure.spring", "spring-cloud-starter-stream-kafka") &&
-		!hasDependency(pom, "com.azure.spring", "spring-kafka") {
-		return nil
+// eventHubsEntities collects the hubs and their consumer groups, in the order they are found.
+type eventHubsEntities struct {
+	hubs           []string
+	consumerGroups map[string][]string // hub name -> consumer group names
+}
+
+// addHub adds the hub, and the consumer group if it's not empty or the default consumer group "$Default", which
+// exists in every hub.
+func (e *eventHubsEntities) addHub(hub string, consumerGroup string) {
+	if hub = strings.TrimSpace(hub); hub == "" {
+		return
 	}
-	var hubs []string
-	if hasDependency(pom, "com.azure.spring", "spring-cloud-azure-stream-binder-eventhubs") ||
-		hasDependency(pom, "org.springframework.cloud", "spring-cloud-starter-stream-kafka") {
-		hubs = internal.AppendAndDistinct(hubs, internal.GetDistinctBindingDestinationValues(properties))
+	e.hubs = internal.AppendAndDistinctInOrder(e.hubs, hub)
+	if consumerGroup = strings.TrimSpace(consumerGroup); consumerGroup == "" ||
+		strings.EqualFold(consumerGroup, "$Default") {
+		return
 	}
-	if hasDependency(pom, "com.azure.spring", "spring-cloud-azure-starter-eventhubs") {
-		var targetPropertyNames = []string{
-			"spring.cloud.azure.eventhubs.event-hub-name",
-			"spring.cloud.azure.eventhubs.producer.event-hub-name",
-			"spring.cloud.azure.eventhubs.consumer.event-hub-name",
-			"spring.cloud.azure.eventhubs.processor.event-hub-name",
+	if e.consumerGroups == nil {
+		e.consumerGroups = make(map[string][]string)
+	}
+	e.consumerGroups[hub] = internal.AppendAndDistinctInOrder(e.consumerGroups[hub], consumerGroup)
+}
+
+// addHubs adds the hubs, each hub is consumed by all the consumer groups.
+func (e *eventHubsEntities) addHubs(hubs []string, consumerGroups []string) {
+	for _, hub := range hubs {
+		e.addHub(hub, "")
+		for _, consumerGroup := range consumerGroups {
+			e.addHub(hub, consumerGroup)
 		}
-		eventHubsNamePropertyMap := map[string]string{}
-		for _, propertyName := range targetPropertyNames {
-			if propertyValue, ok := properties[propertyName]; ok {
-				eventHubsNamePropertyMap[propertyName] = propertyValue
-			}
+	}
+}
+
+func (e *eventHubsEntities) toService() AzureEventHubs {
+	return AzureEventHubs{Hubs: e.hubs, ConsumerGroups: e.consumerGroups}
+}
+
+// usesKafkaOnEventHubs checks whether the application uses Event Hubs by Kafka clients, which are Spring Kafka, Spring
+// Cloud Stream Kafka binder or kafka-clients, or bootstrap servers pointing at the Kafka endpoint of Event Hubs. Kafka
+// clients are considered to use Event Hubs unless their bootstrap servers are an external Kafka cluster, see
+// internal.IsExternalKafkaServer. The external servers are also returned.
+func usesKafkaOnEventHubs(pom internal.Pom, properties internal.PropertyValues) (bool, []string) {
+	servers := internal.GetKafkaBootstrapServers(properties)
+	for _, server := range servers {
+		if internal.IsEventHubsKafkaServer(server) {
+			return true, nil
+		}
+	}
+	if !hasDependency(pom, "org.springframework.kafka", "spring-kafka") &&
+		!hasDependency(pom, "org.apache.kafka", "kafka-clients") &&
+		!hasKafkaBinderDependency(pom) {
+		return false, nil
+	}
+	var externalServers []string
+	for _, server := range servers {
+		if internal.IsExternalKafkaServer(server) {
+			externalServers = append(externalServers, server)
 		}
-		hubs = internal.AppendAndDistinct(hubs, internal.DistinctMapValues(eventHubsNamePropertyMap))
 	}
-	return addApplicationRelatedBackingServiceToResult(result, applicationName, DefaultEventHubsServiceName,
-		AzureEventHubs{Hubs: hubs})
+	return len(externalServers) == 0, externalServers
 }
 
-func detectStorageAccount(result *ProjectAnalysisResult, applicationName string, pom internal.Pom,
-	properties map[string]string) error {
-	if !hasDependency(pom, "com.azure.spring", "spring-cloud-azure-stream-binder-eventhubs") &&
-		!hasDependency(pom, "com.azure.spring", "spring-cloud-azure-starter-integration-eventhubs") &&
-		!hasDependency(pom, "com.azure.spring", "spring-messaging-azure-eventhubs") {
-		return nil
+// detectKafkaOnEventHubs is the builtin condition of usesKafkaOnEventHubs. If the bootstrap servers are an external
+// Kafka cluster, an info diagnostic is added.
+func detectKafkaOnEventHubs(ctx detectionContext) bool {
+	usesKafka, externalServers := usesKafkaOnEventHubs(ctx.pom, ctx.properties)
+	if len(externalServers) > 0 {
+		ctx.result.Diagnostics = append(ctx.result.Diagnostics, Diagnostic{
+			Severity:        DiagnosticSeverityInfo,
+			ApplicationName: ctx.applicationName,
+			FilePath:        ctx.pom.PomFilePath,
+			Message: fmt.Sprintf("Kafka bootstrap servers %s are not Event Hubs, no Event Hubs is created",
+				strings.Join(externalServers, ", ")),
+		})
+	}
+	return usesKafka
+}
+
+// oracleDependencies are the Oracle Database drivers, group id -> artifact ids.
+var oracleDependencies = map[string][]string{
+	"com.oracle.database.jdbc":  {"ojdbc6", "ojdbc8", "ojdbc10", "ojdbc11"},
+	"com.oracle.database.r2dbc": {"oracle-r2dbc"},
+	"io.quarkus":                {"quarkus-jdbc-oracle", "quarkus-reactive-oracle-client"},
+}
+
+// detectOracleDatabase adds a warning diagnostic if the application uses Oracle Database, by its drivers or by the
+// datasource URL, because there is no Azure service to provision for it.
+func detectOracleDatabase(ctx detectionContext) {
+	usesOracle := hasAnyDependency(ctx.pom, oracleDependencies) ||
+		slices.ContainsFunc(getDatasources(ctx.properties), func(datasource Datasource) bool {
+			return datasource.Vendor == "oracle"
+		}) ||
+		strings.EqualFold(internal.GetFirstPropertyValue(ctx.properties, "datasources.default.dialect"), "ORACLE")
+	if !usesOracle {
+		return
+	}
+	ctx.result.Diagnostics = append(ctx.result.Diagnostics, Diagnostic{
+		Severity:        DiagnosticSeverityWarning,
+		ApplicationName: ctx.applicationName,
+		FilePath:        ctx.pom.PomFilePath,
+		Message:         "Oracle Database is unsupported, it needs manual provisioning",
+	})
+}
+
+// detectManuallyProvisionedDatabases adds a warning diagnostic for each database used by the application which azd
+// can't provision, the converter skips their resources.
+func detectManuallyProvisionedDatabases(ctx detectionContext) {
+	var serviceNames []string
+	for name := range ctx.result.ApplicationToBackingService[ctx.applicationName] {
+		serviceNames = append(serviceNames, name)
+	}
+	sort.Strings(serviceNames)
+	for _, serviceName := range serviceNames {
+		service := ctx.result.Services[serviceName]
+		var database string
+		switch service.(type) {
+		case AzureSqlDatabase:
+			database = "Azure SQL Database"
+		case AzureDatabaseForMariadb:
+			database = "Azure Database for MariaDB"
+		default:
+			continue
+		}
+		// The service may be renamed when merging the modules, so the database is named by its database name.
+		if databaseName, _ := getDatabaseName(service); databaseName != "" {
+			database += " " + databaseName
+		}
+		ctx.result.Diagnostics = append(ctx.result.Diagnostics, Diagnostic{
+			Severity:        DiagnosticSeverityWarning,
+			ApplicationName: ctx.applicationName,
+			FilePath:        ctx.pom.PomFilePath,
+			Message:         database + " is unsupported by azd, it needs manual provisioning",
+		})
 	}
-	var containers []string
-	if (hasDependency(pom, "com.azure.spring", "spring-cloud-azure-stream-binder-eventhubs") &&
-		containsInKeywordInBindingName(properties)) ||
-		hasDependency(pom, "com.azure.spring", "spring-cloud-azure-starter-integration-eventhubs") ||
-		hasDependency(pom, "com.azure.spring", "spring-messaging-azure-eventhubs") {
-		containerNamePropertyMap := make(map[string]string)
-		for key, value := range properties {
-			if strings.HasSuffix(key, "spring.cloud.azure.eventhubs.processor.checkpoint-store.container-name") {
-				containerNamePropertyMap[key] = value
+}
+
+func hasKafkaBinderDependency(pom internal.Pom) bool {
+	return hasDependency(pom, "org.springframework.cloud", "spring-cloud-starter-stream-kafka") ||
+		hasDependency(pom, "org.springframework.cloud", "spring-cloud-stream-binder-kafka")
+}
+
+// hasAnyDependency checks whether the pom has any of the dependencies, group id -> artifact ids.
+func hasAnyDependency(pom internal.Pom, dependencies map[string][]string) bool {
+	for groupId, artifactIds := range dependencies {
+		for _, artifactId := range artifactIds {
+			if hasDependency(pom, groupId, artifactId) {
+				return true
 			}
 		}
-		containers = internal.AppendAndDistinct(containers, internal.DistinctMapValues(containerNamePropertyMap))
 	}
-	return addApplicationRelatedBackingServiceToResult(result, applicationName, DefaultStorageServiceName,
-		AzureStorageAccount{Containers: containers})
+	return false
 }
 
 func hasDependency(pom internal.Pom, groupId string, artifactId string) bool {
@@ -252,16 +616,25 @@ func hasDependency(pom internal.Pom, groupId string, artifactId string) bool {
 	return false
 }
 
-func getDatabaseNameFromSpringDataSourceUrlProperty(properties map[string]string) string {
-	databaseName := ""
-	databaseNamePropertyValue, ok := properties["spring.datasource.url"]
-	if ok {
-		databaseName = internal.GetDatabaseName(databaseNamePropertyValue)
+// resolveAnnotationValues resolves the placeholders in the annotation attribute values, and splits comma-separated
+// values like "${app.topics}" resolved to "orders,payments". Values which can not be resolved are ignored.
+func resolveAnnotationValues(values []string, properties internal.PropertyValues) []string {
+	var result []string
+	for _, value := range values {
+		resolved, ok := internal.ResolvePlaceholders(value, properties)
+		if !ok {
+			continue
+		}
+		for _, item := range strings.Split(resolved, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				result = internal.AppendAndDistinctInOrder(result, item)
+			}
+		}
 	}
-	return databaseName
+	return result
 }
 
-func containsInKeywordInBindingName(properties map[string]string) bool {
+func containsInKeywordInBindingName(properties internal.PropertyValues) bool {
 	bindingDestinations := internal.GetBindingDestinationMap(properties)
 	for bindingName := range bindingDestinations {
 		if strings.Contains(bindingName, "-in-") { // Example: consume-in-0
@@ -270,3 +643,76 @@ func containsInKeywordInBindingName(properties map[string]string) bool {
 	}
 	return false
 }
+
+// newVersionDiagnostics reports the versions which can not run together: Spring Boot 3.x and later require Java 17.
+func newVersionDiagnostics(applicationName string, pomFilePath string, javaVersion string,
+	springBootVersion string) []Diagnostic {
+	switch internal.GetMajorVersion(springBootVersion) {
+	case "", "1", "2":
+		return nil
+	}
+	if version, err := strconv.Atoi(javaVersion); err != nil || version >= 17 {
+		return nil
+	}
+	return []Diagnostic{{
+		Severity:        DiagnosticSeverityWarning,
+		ApplicationName: applicationName,
+		FilePath:        pomFilePath,
+		Message: fmt.Sprintf("Spring Boot %s requires Java 17 or later, but Java %s is declared", springBootVersion,
+			javaVersion),
+	}}
+}
+
+func newSkippedConfigFileDiagnostic(projectRootPath string, applicationName string,
+	skippedFile *internal.ConfigFileError) Diagnostic {
+	filePath, err := filepath.Rel(projectRootPath, skippedFile.FilePath)
+	if err != nil {
+		filePath = skippedFile.FilePath
+	}
+	return Diagnostic{
+		Severity:        DiagnosticSeverityWarning,
+		ApplicationName: applicationName,
+		FilePath:        filePath,
+		Line:            skippedFile.Line,
+		Column:          skippedFile.Column,
+		Message:         fmt.Sprintf("skipped malformed config file: %v", skippedFile.Err),
+	}
+}
+
+func newSkippedHelmTemplateDiagnostic(projectRootPath string, applicationName string, templatePath string) Diagnostic {
+	filePath, err := filepath.Rel(projectRootPath, templatePath)
+	if err != nil {
+		filePath = templatePath
+	}
+	return Diagnostic{
+		Severity:        DiagnosticSeverityWarning,
+		ApplicationName: applicationName,
+		FilePath:        filePath,
+		Message:         "skipped Helm template, the config it deploys is only known after rendering",
+	}
+}
+
+// newApplicationProperties converts the property values, file paths are converted to be relative to the project root
+// path. Nil is returned if there is no property.
+func newApplicationProperties(projectRootPath string, values internal.PropertyValues) map[string]PropertyValue {
+	if len(values) == 0 {
+		return nil
+	}
+	result := make(map[string]PropertyValue, len(values))
+	for canonicalName, value := range values {
+		result[canonicalName] = newPropertyValue(projectRootPath, value)
+	}
+	return result
+}
+
+func newPropertyValue(projectRootPath string, value internal.PropertyValue) PropertyValue {
+	origin := PropertyOrigin(value.Origin)
+	if filePath, err := filepath.Rel(projectRootPath, origin.FilePath); err == nil {
+		origin.FilePath = filePath
+	}
+	result := PropertyValue{Name: value.Name, Value: value.Value, Origin: origin}
+	for _, overridden := range value.Overrides {
+		result.Overrides = append(result.Overrides, newPropertyValue(projectRootPath, overridden))
+	}
+	return result
+}
@@ -1,6 +1,8 @@
 package analyzer
 
 import (
+	"errors"
+	"os"
 	"path/filepath"
 	"testing"
 
@@ -28,7 +30,44 @@ func TestAnalyzeJavaProject(t *testing.T) {
 			expected: ProjectAnalysisResult{
 				Name: "java-multiple-modules",
 				Applications: map[string]Application{
-					"application": {"application"},
+					"application": {
+						ProjectRelativePath: "application",
+						Framework:           FrameworkSpringBoot,
+						Properties: map[string]PropertyValue{
+							"service.message": {
+								Name:  "service.message",
+								Value: "Hello, World",
+								Origin: PropertyOrigin{
+									FilePath: filepath.Join("application", "src", "main", "resources",
+										"application.properties"),
+									Line: 1,
+								},
+							},
+						},
+						WebApplicationType: WebApplicationTypeServlet,
+						Port:               8080,
+						HealthProbes: &HealthProbes{
+							Port:          8080,
+							LivenessPath:  "/actuator/health",
+							ReadinessPath: "/actuator/health",
+							StartupPath:   "/actuator/health",
+						},
+						Ingress:           IngressTypeExternal,
+						JavaVersion:       "17",
+						SpringBootVersion: "3.3.0",
+						ServiceEvidence: map[string][]Evidence{
+							DefaultMysqlServiceName: {
+								{Kind: EvidenceKindDependency, Value: "com.mysql:mysql-connector-j",
+									FilePath: filepath.Join("application", "pom.xml")},
+								{Kind: EvidenceKindCondition, Value: "no-datasource-url"},
+							},
+							DefaultPostgresqlServiceName: {
+								{Kind: EvidenceKindDependency, Value: "org.postgresql:postgresql",
+									FilePath: filepath.Join("application", "pom.xml")},
+								{Kind: EvidenceKindCondition, Value: "no-datasource-url"},
+							},
+						},
+					},
 				},
 				Services: map[string]Service{
 					"application":                AzureContainerApp{},
@@ -46,6 +85,145 @@ func TestAnalyzeJavaProject(t *testing.T) {
 				},
 			},
 		},
+		{
+			name:             "java-gradle",
+			workingDirectory: filepath.Join("testdata", "java-gradle"),
+			expected: ProjectAnalysisResult{
+				Name: "java-gradle",
+				Applications: map[string]Application{
+					"orders": {
+						ProjectRelativePath: "orders",
+						Framework:           FrameworkQuarkus,
+						Properties: map[string]PropertyValue{
+							"quarkus.http.port": {
+								Name:  "quarkus.http.port",
+								Value: "8081",
+								Origin: PropertyOrigin{
+									FilePath: filepath.Join("orders", "src", "main", "resources",
+										"application.properties"),
+									Line: 1,
+								},
+							},
+							"quarkus.datasource.jdbc.url": {
+								Name:  "quarkus.datasource.jdbc.url",
+								Value: "jdbc:postgresql://orders-db:5432/orders",
+								Origin: PropertyOrigin{
+									FilePath: filepath.Join("orders", "src", "main", "resources",
+										"application.properties"),
+									Line:    3,
+									Profile: "prod",
+								},
+								Overrides: []PropertyValue{
+									{
+										Name:  "quarkus.datasource.jdbc.url",
+										Value: "jdbc:postgresql://localhost:5432/dev-orders",
+										Origin: PropertyOrigin{
+											FilePath: filepath.Join("orders", "src", "main", "resources",
+												"application.properties"),
+											Line: 2,
+										},
+									},
+								},
+							},
+						},
+						WebApplicationType: WebApplicationTypeReactive,
+						Port:               8081,
+						HealthProbes: &HealthProbes{
+							Port:          8081,
+							LivenessPath:  "/q/health/live",
+							ReadinessPath: "/q/health/ready",
+							StartupPath:   "/q/health/started",
+						},
+						Ingress: IngressTypeExternal,
+						Datasources: []Datasource{{PropertyName: "quarkus.datasource.jdbc.url", Vendor: "postgresql",
+							Host: "orders-db", Port: 5432, DatabaseName: "orders", ServiceName: DefaultPostgresqlServiceName}},
+						ServiceEvidence: map[string][]Evidence{
+							DefaultPostgresqlServiceName: {{Kind: EvidenceKindProperty, Value: "quarkus.datasource.jdbc.url",
+								FilePath: filepath.Join("orders", "src", "main", "resources", "application.properties"),
+								Line:     3}},
+							DefaultRedisServiceName: {{Kind: EvidenceKindDependency, Value: "io.quarkus:quarkus-redis-client",
+								FilePath: filepath.Join("orders", "build.gradle")}},
+						},
+					},
+					"inventory": {
+						ProjectRelativePath: "inventory",
+						Framework:           FrameworkMicronaut,
+						Properties: map[string]PropertyValue{
+							"micronaut.server.port": {
+								Name:  "micronaut.server.port",
+								Value: "8083",
+								Origin: PropertyOrigin{
+									FilePath: filepath.Join("inventory", "src", "main", "resources",
+										"application.yml"),
+									Line:   3,
+									Column: 11,
+								},
+							},
+							"datasources.default.url": {
+								Name:  "datasources.default.url",
+								Value: "jdbc:mysql://inventory-db:3306/inventory",
+								Origin: PropertyOrigin{
+									FilePath: filepath.Join("inventory", "src", "main", "resources",
+										"application.yml"),
+									Line:   6,
+									Column: 10,
+								},
+							},
+							"datasources.default.dialect": {
+								Name:  "datasources.default.dialect",
+								Value: "MYSQL",
+								Origin: PropertyOrigin{
+									FilePath: filepath.Join("inventory", "src", "main", "resources",
+										"application.yml"),
+									Line:   7,
+									Column: 14,
+								},
+							},
+						},
+						WebApplicationType: WebApplicationTypeReactive,
+						Port:               8083,
+						HealthProbes: &HealthProbes{
+							Port:          8083,
+							LivenessPath:  "/health/liveness",
+							ReadinessPath: "/health/readiness",
+							StartupPath:   "/health/liveness",
+						},
+						Ingress: IngressTypeExternal,
+						Datasources: []Datasource{{PropertyName: "datasources.default.url", Vendor: "mysql",
+							Host: "inventory-db", Port: 3306, DatabaseName: "inventory", ServiceName: DefaultMysqlServiceName}},
+						ServiceEvidence: map[string][]Evidence{
+							DefaultMysqlServiceName: {{Kind: EvidenceKindProperty, Value: "datasources.default.url",
+								FilePath: filepath.Join("inventory", "src", "main", "resources", "application.yml"),
+								Line:     6}},
+							DefaultRedisServiceName: {{Kind: EvidenceKindDependency,
+								Value:    "io.micronaut.redis:micronaut-redis-lettuce",
+								FilePath: filepath.Join("inventory", "build.gradle.kts")}},
+						},
+					},
+				},
+				Services: map[string]Service{
+					"orders":                     AzureContainerApp{},
+					"inventory":                  AzureContainerApp{},
+					DefaultPostgresqlServiceName: AzureDatabaseForPostgresql{DatabaseName: "orders"},
+					DefaultMysqlServiceName:      AzureDatabaseForMysql{DatabaseName: "inventory"},
+					DefaultRedisServiceName:      AzureCacheForRedis{},
+				},
+				ApplicationToHostingService: map[string]string{
+					"orders":    "orders",
+					"inventory": "inventory",
+				},
+				ApplicationToBackingService: map[string]map[string]interface{}{
+					"orders": {
+						DefaultPostgresqlServiceName: "",
+						DefaultRedisServiceName:      "",
+					},
+					"inventory": {
+						DefaultMysqlServiceName: "",
+						DefaultRedisServiceName: "",
+					},
+				},
+			},
+		},
 	}
 
 	for _, tt := range tests {
@@ -61,6 +239,17 @@ func TestAnalyzeJavaProject(t *testing.T) {
 	}
 }
 
+func TestAnalyzeJavaProjectWithInvalidDetectionRules(t *testing.T) {
+	// The project has no application, the rules are still validated.
+	projectRootPath := t.TempDir()
+	userRulesPath := filepath.Join(t.TempDir(), "rules.yaml")
+	require.NoError(t, os.WriteFile(userRulesPath, []byte("rules:\n  - id: redis\n    serviceType: redis\n"), 0600))
+	_, err := AnalyzeJavaProjectWithOptions(projectRootPath, AnalyzeOptions{DetectionRulesPath: userRulesPath})
+	var configFileError *internal.ConfigFileError
+	require.ErrorAs(t, err, &configFileError)
+	require.Equal(t, userRulesPath, configFileError.FilePath)
+}
+
 func TestAnalyzePomProject(t *testing.T) {
 	tests := []struct {
 		name     string
@@ -140,7 +329,25 @@ func TestAnalyzePomProject(t *testing.T) {
 			},
 			expected: ProjectAnalysisResult{
 				Applications: map[string]Application{
-					"application": {"application"},
+					"application": {
+						ProjectRelativePath: "application",
+						Framework:           FrameworkSpringBoot,
+						WebApplicationType:  WebApplicationTypeNone,
+						JavaVersion:         "17",
+						SpringBootVersion:   "3.3.0",
+						ServiceEvidence: map[string][]Evidence{
+							DefaultMysqlServiceName: {
+								{Kind: EvidenceKindDependency, Value: "com.mysql:mysql-connector-j",
+									FilePath: filepath.Join("application", "pom.xml")},
+								{Kind: EvidenceKindCondition, Value: "no-datasource-url"},
+							},
+							DefaultPostgresqlServiceName: {
+								{Kind: EvidenceKindDependency, Value: "org.postgresql:postgresql",
+									FilePath: filepath.Join("application", "pom.xml")},
+								{Kind: EvidenceKindCondition, Value: "no-datasource-url"},
+							},
+						},
+					},
 				},
 				Services: map[string]Service{
 					"application":                AzureContainerApp{},
@@ -170,7 +377,8 @@ func TestAnalyzePomProject(t *testing.T) {
 			testPom := tt.testPoms[0]
 			pomFileAbsolutePath := filepath.Join(workingDir, testPom.PomFileRelativePath)
 
-			project, err := analyzePomProject(workingDir, pomFileAbsolutePath)
+			project, err := analyzePomProject(workingDir, pomFileAbsolutePath, AnalyzeOptions{},
+				loadTestDetectionRules(t))
 			if err != nil {
 				t.Fatalf("analyzePomProject failed: %v", err)
 			}
@@ -179,3 +387,531 @@ func TestAnalyzePomProject(t *testing.T) {
 		})
 	}
 }
+
+func TestNewSkippedConfigFileDiagnostic(t *testing.T) {
+	projectRootPath := filepath.Join("root")
+	skippedFile := &internal.ConfigFileError{
+		FilePath: filepath.Join(projectRootPath, "application", "src", "main", "resources", "application.yml"),
+		Line:     4,
+		Err:      errors.New("yaml: line 4: mapping values are not allowed in this context"),
+	}
+	diagnostic := newSkippedConfigFileDiagnostic(projectRootPath, "application", skippedFile)
+	require.Equal(t, Diagnostic{
+		Severity:        DiagnosticSeverityWarning,
+		ApplicationName: "application",
+		FilePath:        filepath.Join("application", "src", "main", "resources", "application.yml"),
+		Line:            4,
+		Message: "skipped malformed config file: " +
+			"yaml: line 4: mapping values are not allowed in this context",
+	}, diagnostic)
+	require.Equal(t, "warning: "+filepath.Join("application", "src", "main", "resources", "application.yml")+
+		":4 (application: application): skipped malformed config file: "+
+		"yaml: line 4: mapping values are not allowed in this context", diagnostic.String())
+}
+
+func TestNewApplicationProperties(t *testing.T) {
+	projectRootPath := filepath.Join("testdata", "project")
+	values := internal.PropertyValues{
+		"app.name": {
+			Name:  "app.name",
+			Value: "dev",
+			Origin: internal.PropertyOrigin{
+				FilePath: filepath.Join(projectRootPath, "app", "application-dev.yml"),
+				Line:     2,
+				Column:   9,
+				Profile:  "dev",
+			},
+			Overrides: []internal.PropertyValue{
+				{
+					Name:   "app.name",
+					Value:  "default",
+					Origin: internal.PropertyOrigin{FilePath: filepath.Join(projectRootPath, "app", "application.yml")},
+				},
+			},
+		},
+	}
+	expected := map[string]PropertyValue{
+		"app.name": {
+			Name:  "app.name",
+			Value: "dev",
+			Origin: PropertyOrigin{
+				FilePath: filepath.Join("app", "application-dev.yml"),
+				Line:     2,
+				Column:   9,
+				Profile:  "dev",
+			},
+			Overrides: []PropertyValue{
+				{
+					Name:   "app.name",
+					Value:  "default",
+					Origin: PropertyOrigin{FilePath: filepath.Join("app", "application.yml")},
+				},
+			},
+		},
+	}
+	require.Equal(t, expected, newApplicationProperties(projectRootPath, values))
+	require.Nil(t, newApplicationProperties(projectRootPath, internal.PropertyValues{}))
+}
+
+func TestGetServiceBusDestinationsInAnnotations(t *testing.T) {
+	annotations := []internal.JavaAnnotation{
+		{Name: "JmsListener", Attributes: map[string][]string{"destination": {"${app.queue}"}}},
+		{Name: "JmsListener", Attributes: map[string][]string{"destination": {"orders"}, "subscription": {"audit"}}},
+		{Name: "JmsListener", Attributes: map[string][]string{"destination": {"payments"},
+			"containerFactory": {"topicJmsListenerContainerFactory"}}},
+		{Name: "ServiceBusListener", Attributes: map[string][]string{"destination": {"refunds"}}},
+		{Name: "ServiceBusListener", Attributes: map[string][]string{"destination": {"events"}, "group": {"sub"}}},
+		{Name: "KafkaListener", Attributes: map[string][]string{"topics": {"ignored"}}},
+		{Name: "JmsListener", Attributes: map[string][]string{"destination": {"${app.missing}"}}},
+	}
+	var entities serviceBusEntities
+	getServiceBusDestinationsInAnnotations(annotations,
+		internal.NewTestPropertyValues(map[string]string{"app.queue": "tasks"}), false, &entities)
+	require.Equal(t, AzureServiceBus{
+		Queues:        []string{"tasks", "refunds"},
+		Topics:        []string{"orders", "payments", "events"},
+		Subscriptions: map[string][]string{"orders": {"audit"}, "events": {"sub"}},
+	}, entities.toService())
+}
+
+func TestGetServiceBusDestinationsInAnnotationsWithPubSubDomain(t *testing.T) {
+	annotations := []internal.JavaAnnotation{
+		{Name: "JmsListener", Attributes: map[string][]string{"destination": {"orders"}}},
+		{Name: "JmsListener", Attributes: map[string][]string{"destination": {"tasks"},
+			"containerFactory": {"queueJmsListenerContainerFactory"}}},
+	}
+	var entities serviceBusEntities
+	getServiceBusDestinationsInAnnotations(annotations, nil, true, &entities)
+	require.Equal(t, AzureServiceBus{Queues: []string{"tasks"}, Topics: []string{"orders"}}, entities.toService())
+}
+
+func TestDetectServiceBus(t *testing.T) {
+	tests := []struct {
+		name       string
+		artifactId string
+		properties map[string]string
+		expected   AzureServiceBus
+	}{
+		{
+			name:       "stream binder",
+			artifactId: "spring-cloud-azure-stream-binder-servicebus",
+			properties: map[string]string{
+				"spring.cloud.stream.bindings.consume-in-0.destination":                     "orders",
+				"spring.cloud.stream.bindings.consume-in-0.group":                           "billing",
+				"spring.cloud.stream.servicebus.bindings.consume-in-0.consumer.entity-type": "topic",
+				"spring.cloud.stream.bindings.supply-out-0.destination":                     "orders",
+				"spring.cloud.stream.servicebus.bindings.supply-out-0.producer.entity-type": "topic",
+				"spring.cloud.stream.bindings.audit-in-0.destination":                       "audit",
+				"spring.cloud.stream.bindings.audit-in-0.group":                             "auditor",
+				"spring.cloud.stream.bindings.task-in-0.destination":                        "tasks",
+				"spring.cloud.stream.servicebus.bindings.task-in-0.consumer.entity-type":    "queue",
+				"spring.cloud.stream.bindings.task-in-0.group":                              "ignored",
+			},
+			expected: AzureServiceBus{
+				Queues:        []string{"tasks"},
+				Topics:        []string{"audit", "orders"},
+				Subscriptions: map[string][]string{"audit": {"auditor"}, "orders": {"billing"}},
+			},
+		},
+		{
+			name:       "starter",
+			artifactId: "spring-cloud-azure-starter-servicebus",
+			properties: map[string]string{
+				"spring.cloud.azure.servicebus.entity-name":                 "orders",
+				"spring.cloud.azure.servicebus.entity-type":                 "topic",
+				"spring.cloud.azure.servicebus.processor.subscription-name": "billing",
+				"spring.cloud.azure.servicebus.consumer.entity-name":        "tasks",
+				"spring.cloud.azure.servicebus.consumer.entity-type":        "queue",
+			},
+			expected: AzureServiceBus{
+				Queues:        []string{"tasks"},
+				Topics:        []string{"orders"},
+				Subscriptions: map[string][]string{"orders": {"billing"}},
+			},
+		},
+		{
+			name:       "jms",
+			artifactId: "spring-cloud-azure-starter-servicebus-jms",
+			properties: map[string]string{
+				"spring.jms.servicebus.pub-sub-domain":    "true",
+				"spring.jms.template.default-destination": "notifications",
+			},
+			expected: AzureServiceBus{Topics: []string{"notifications"}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pom, err := internal.ParseTestPom(`<project><dependencies><dependency>
+				<groupId>com.azure.spring</groupId><artifactId>` + tt.artifactId + `</artifactId>
+				</dependency></dependencies></project>`)
+			require.NoError(t, err)
+			result := ProjectAnalysisResult{}
+			require.NoError(t, addApplicationToResult(&result, "app", Application{}))
+			require.NoError(t, detectBackingServices(detectionContext{result: &result, applicationName: "app", pom: pom,
+				properties: internal.NewTestPropertyValues(tt.properties)}, loadTestDetectionRules(t)))
+			require.Equal(t, tt.expected, result.Services[DefaultServiceBusServiceName])
+		})
+	}
+}
+
+func TestResolveAnnotationValues(t *testing.T) {
+	properties := internal.NewTestPropertyValues(map[string]string{"app.topics": "orders, payments"})
+	require.Equal(t, []string{"orders", "payments", "refunds"},
+		resolveAnnotationValues([]string{"${app.topics}", "refunds", "orders", "${app.missing}"}, properties))
+}
+
+func TestDetectWebApplicationType(t *testing.T) {
+	webPom, err := internal.ParseTestPom(`
+		<project>
+			<dependencies>
+				<dependency>
+					<groupId>org.springframework.boot</groupId>
+					<artifactId>spring-boot-starter-web</artifactId>
+				</dependency>
+			</dependencies>
+		</project>`)
+	require.NoError(t, err)
+	webFluxPom, err := internal.ParseTestPom(`
+		<project>
+			<dependencies>
+				<dependency>
+					<groupId>org.springframework.boot</groupId>
+					<artifactId>spring-boot-starter-webflux</artifactId>
+				</dependency>
+			</dependencies>
+		</project>`)
+	require.NoError(t, err)
+	adminServerPom, err := internal.ParseTestPom(`
+		<project>
+			<dependencies>
+				<dependency>
+					<groupId>de.codecentric</groupId>
+					<artifactId>spring-boot-admin-starter-server</artifactId>
+				</dependency>
+			</dependencies>
+		</project>`)
+	require.NoError(t, err)
+	tests := []struct {
+		name        string
+		pom         internal.Pom
+		properties  map[string]string
+		annotations []internal.JavaAnnotation
+		expected    WebApplicationType
+	}{
+		{"web starter", webPom, nil, nil, WebApplicationTypeServlet},
+		{"admin server", adminServerPom, nil, nil, WebApplicationTypeServlet},
+		{"webflux starter", webFluxPom, nil, nil, WebApplicationTypeReactive},
+		{"property", webPom, map[string]string{"spring.main.web-application-type": "NONE"}, nil,
+			WebApplicationTypeNone},
+		{"controller", internal.Pom{}, nil, []internal.JavaAnnotation{{Name: "RestController"}},
+			WebApplicationTypeServlet},
+		{"worker", internal.Pom{}, nil, []internal.JavaAnnotation{{Name: "JmsListener"}}, WebApplicationTypeNone},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			properties := internal.NewTestPropertyValues(tt.properties)
+			require.Equal(t, tt.expected, detectWebApplicationType(tt.pom, properties, tt.annotations))
+		})
+	}
+}
+
+func TestAnalyzeConfigServerProject(t *testing.T) {
+	pomContent := `
+		<project>
+			<dependencies>
+				<dependency>
+					<groupId>org.springframework.cloud</groupId>
+					<artifactId>spring-cloud-config-server</artifactId>
+				</dependency>
+			</dependencies>
+			<build>
+				<plugins>
+					<plugin>
+						<groupId>org.springframework.boot</groupId>
+						<artifactId>spring-boot-maven-plugin</artifactId>
+					</plugin>
+				</plugins>
+			</build>
+		</project>`
+	projectRootPath, err := internal.PrepareTestPomFiles([]internal.TestPom{
+		{PomFileRelativePath: filepath.Join("config-server", "pom.xml"), PomContentString: pomContent},
+	})
+	require.NoError(t, err)
+	defer os.RemoveAll(projectRootPath)
+	resourcesPath := filepath.Join(projectRootPath, "config-server", "src", "main", "resources")
+	require.NoError(t, os.MkdirAll(resourcesPath, 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(resourcesPath, "application.properties"),
+		[]byte("server.port=8888\n"), 0600))
+	pom, err := internal.ParseTestPom(pomContent)
+	require.NoError(t, err)
+
+	result, err := analyzeProject(projectRootPath, filepath.Join(projectRootPath, "config-server", "pom.xml"), pom,
+		AnalyzeOptions{}, loadTestDetectionRules(t))
+	require.NoError(t, err)
+	require.NoError(t, resolveApplicationTopology(&result))
+	application := result.Applications["config-server"]
+	require.Equal(t, WebApplicationTypeServlet, application.WebApplicationType)
+	require.Equal(t, 8888, application.Port)
+	require.Equal(t, []SpringCloudComponent{SpringCloudComponentConfigServer}, application.SpringCloudComponents)
+	require.Equal(t, IngressTypeExternal, application.Ingress)
+}
+
+func TestNewVersionDiagnostics(t *testing.T) {
+	pomFilePath := filepath.Join("application", "pom.xml")
+	require.Empty(t, newVersionDiagnostics("application", pomFilePath, "17", "3.3.0"))
+	require.Empty(t, newVersionDiagnostics("application", pomFilePath, "", ""))
+	require.Equal(t, []Diagnostic{
+		{
+			Severity:        DiagnosticSeverityWarning,
+			ApplicationName: "application",
+			FilePath:        pomFilePath,
+			Message:         "Spring Boot 3.3.0 requires Java 17 or later, but Java 11 is declared",
+		},
+	}, newVersionDiagnostics("application", pomFilePath, "11", "3.3.0"))
+	require.Empty(t, newVersionDiagnostics("application", pomFilePath, "11", "2.7.18"))
+}
+
+func TestDetectEventHubsByKafka(t *testing.T) {
+	springKafkaPom := `
+		<project>
+			<dependencies>
+				<dependency>
+					<groupId>org.springframework.kafka</groupId>
+					<artifactId>spring-kafka</artifactId>
+				</dependency>
+			</dependencies>
+		</project>`
+	kafkaClientsPom := `
+		<project>
+			<dependencies>
+				<dependency>
+					<groupId>org.apache.kafka</groupId>
+					<artifactId>kafka-clients</artifactId>
+				</dependency>
+			</dependencies>
+		</project>`
+	tests := []struct {
+		name                string
+		pom                 string
+		properties          map[string]string
+		annotations         []internal.JavaAnnotation
+		expectedService     Service
+		expectedDiagnostics int
+	}{
+		{
+			name: "spring kafka",
+			pom:  springKafkaPom,
+			properties: map[string]string{
+				"spring.kafka.bootstrap-servers":      "localhost:9092",
+				"spring.kafka.template.default-topic": "orders",
+			},
+			annotations: []internal.JavaAnnotation{
+				{Name: "KafkaListener", Attributes: map[string][]string{"topics": {"${app.topic}", "orders"}}},
+			},
+			expectedService: AzureEventHubs{Hubs: []string{"orders", "payments"}},
+		},
+		{
+			name: "kafka clients with event hubs bootstrap servers",
+			pom:  kafkaClientsPom,
+			properties: map[string]string{
+				"bootstrap.servers": "my-namespace.servicebus.windows.net:9093",
+			},
+			expectedService: AzureEventHubs{},
+		},
+		{
+			name: "event hubs bootstrap servers without kafka dependency",
+			pom:  `<project></project>`,
+			properties: map[string]string{
+				"spring.kafka.bootstrap-servers": "my-namespace.servicebus.windows.net:9093",
+			},
+			expectedService: AzureEventHubs{},
+		},
+		{
+			name: "external kafka cluster",
+			pom:  springKafkaPom,
+			properties: map[string]string{
+				"spring.kafka.bootstrap-servers": "pkc-4r087.westeurope.azure.confluent.cloud:9092",
+			},
+			expectedDiagnostics: 1,
+		},
+		{
+			name:       "no kafka",
+			pom:        `<project></project>`,
+			properties: map[string]string{"spring.kafka.bootstrap-servers": "localhost:9092"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pom, err := internal.ParseTestPom(tt.pom)
+			require.NoError(t, err)
+			result := ProjectAnalysisResult{}
+			require.NoError(t, addApplicationToResult(&result, "app", Application{}))
+			properties := map[string]string{"app.topic": "payments"}
+			for name, value := range tt.properties {
+				properties[name] = value
+			}
+			require.NoError(t, detectBackingServices(detectionContext{result: &result, applicationName: "app", pom: pom,
+				properties: internal.NewTestPropertyValues(properties), annotations: tt.annotations},
+				loadTestDetectionRules(t)))
+			require.Equal(t, tt.expectedService, result.Services[DefaultEventHubsServiceName])
+			require.Len(t, result.Diagnostics, tt.expectedDiagnostics)
+		})
+	}
+}
+
+func TestDetectEventHubsConsumerGroups(t *testing.T) {
+	tests := []struct {
+		name        string
+		artifactId  string
+		groupId     string
+		properties  map[string]string
+		annotations []internal.JavaAnnotation
+		expected    AzureEventHubs
+	}{
+		{
+			name:       "stream binder",
+			groupId:    "com.azure.spring",
+			artifactId: "spring-cloud-azure-stream-binder-eventhubs",
+			properties: map[string]string{
+				"spring.cloud.stream.bindings.consume-in-0.destination": "telemetry",
+				"spring.cloud.stream.bindings.consume-in-0.group":       "alerts",
+				"spring.cloud.stream.bindings.audit-in-0.destination":   "telemetry",
+				"spring.cloud.stream.bindings.audit-in-0.group":         "$Default",
+				"spring.cloud.stream.bindings.supply-out-0.destination": "orders",
+			},
+			expected: AzureEventHubs{
+				Hubs:           []string{"telemetry", "orders"},
+				ConsumerGroups: map[string][]string{"telemetry": {"alerts"}},
+			},
+		},
+		{
+			name:       "starter",
+			groupId:    "com.azure.spring",
+			artifactId: "spring-cloud-azure-starter-eventhubs",
+			properties: map[string]string{
+				"spring.cloud.azure.eventhubs.event-hub-name":           "telemetry",
+				"spring.cloud.azure.eventhubs.processor.consumer-group": "alerts",
+				"spring.cloud.azure.eventhubs.producer.event-hub-name":  "orders",
+			},
+			annotations: []internal.JavaAnnotation{
+				{Name: "EventHubsListener", Attributes: map[string][]string{
+					"destination": {"payments"}, "group": {"${app.group}"}}},
+			},
+			expected: AzureEventHubs{
+				Hubs:           []string{"telemetry", "orders", "payments"},
+				ConsumerGroups: map[string][]string{"telemetry": {"alerts"}, "payments": {"billing"}},
+			},
+		},
+		{
+			name:       "spring kafka",
+			groupId:    "org.springframework.kafka",
+			artifactId: "spring-kafka",
+			properties: map[string]string{
+				"spring.kafka.consumer.group-id": "order-service",
+			},
+			annotations: []internal.JavaAnnotation{
+				{Name: "KafkaListener", Attributes: map[string][]string{"topics": {"orders"}}},
+				{Name: "KafkaListener", Attributes: map[string][]string{
+					"topics": {"payments"}, "groupId": {"${app.group}"}}},
+			},
+			expected: AzureEventHubs{
+				Hubs:           []string{"orders", "payments"},
+				ConsumerGroups: map[string][]string{"orders": {"order-service"}, "payments": {"billing"}},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pom, err := internal.ParseTestPom(`<project><dependencies><dependency>
+				<groupId>` + tt.groupId + `</groupId><artifactId>` + tt.artifactId + `</artifactId>
+				</dependency></dependencies></project>`)
+			require.NoError(t, err)
+			result := ProjectAnalysisResult{}
+			require.NoError(t, addApplicationToResult(&result, "app", Application{}))
+			properties := map[string]string{"app.group": "billing"}
+			for name, value := range tt.properties {
+				properties[name] = value
+			}
+			require.NoError(t, detectBackingServices(detectionContext{result: &result, applicationName: "app", pom: pom,
+				properties: internal.NewTestPropertyValues(properties), annotations: tt.annotations},
+				loadTestDetectionRules(t)))
+			require.Equal(t, tt.expected, result.Services[DefaultEventHubsServiceName])
+		})
+	}
+}
+
+func TestDetectOracleDatabase(t *testing.T) {
+	tests := []struct {
+		name                string
+		pom                 string
+		properties          map[string]string
+		expectedDiagnostics int
+	}{
+		{
+			name: "oracle driver",
+			pom: `<project><dependencies><dependency><groupId>com.oracle.database.jdbc</groupId>
+				<artifactId>ojdbc11</artifactId></dependency></dependencies></project>`,
+			expectedDiagnostics: 1,
+		},
+		{
+			name:                "oracle datasource url",
+			pom:                 `<project></project>`,
+			properties:          map[string]string{"spring.datasource.url": "jdbc:oracle:thin:@localhost:1521/orders"},
+			expectedDiagnostics: 1,
+		},
+		{
+			name: "oracle secondary datasource url",
+			pom:  `<project></project>`,
+			properties: map[string]string{
+				"spring.datasource.legacy.url": "jdbc:oracle:thin:@localhost:1521:ORCL",
+			},
+			expectedDiagnostics: 1,
+		},
+		{
+			name:                "micronaut data oracle dialect",
+			pom:                 `<project></project>`,
+			properties:          map[string]string{"datasources.default.dialect": "oracle"},
+			expectedDiagnostics: 1,
+		},
+		{
+			name:       "postgresql",
+			pom:        `<project></project>`,
+			properties: map[string]string{"spring.datasource.url": "jdbc:postgresql://localhost:5432/orders"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pom, err := internal.ParseTestPom(tt.pom)
+			require.NoError(t, err)
+			result := ProjectAnalysisResult{}
+			detectOracleDatabase(detectionContext{result: &result, applicationName: "app", pom: pom,
+				properties: internal.NewTestPropertyValues(tt.properties)})
+			require.Len(t, result.Diagnostics, tt.expectedDiagnostics)
+			for _, diagnostic := range result.Diagnostics {
+				require.Equal(t, DiagnosticSeverityWarning, diagnostic.Severity)
+			}
+		})
+	}
+}
+
+func TestDetectManuallyProvisionedDatabases(t *testing.T) {
+	result := ProjectAnalysisResult{}
+	require.NoError(t, addApplicationToResult(&result, "app", Application{}))
+	services := map[string]Service{
+		DefaultPostgresqlServiceName: AzureDatabaseForPostgresql{},
+		DefaultSqlServerServiceName:  AzureSqlDatabase{DatabaseName: "orders"},
+		DefaultMariadbServiceName:    AzureDatabaseForMariadb{DatabaseName: "inventory"},
+	}
+	for name, service := range services {
+		require.NoError(t, addApplicationRelatedBackingServiceToResult(&result, "app", name, service))
+	}
+	pom, err := internal.ParseTestPom(`<project></project>`)
+	require.NoError(t, err)
+	detectManuallyProvisionedDatabases(detectionContext{result: &result, applicationName: "app", pom: pom})
+	require.Equal(t, []Diagnostic{
+		{Severity: DiagnosticSeverityWarning, ApplicationName: "app",
+			Message: "Azure Database for MariaDB inventory is unsupported by azd, it needs manual provisioning"},
+		{Severity: DiagnosticSeverityWarning, ApplicationName: "app",
+			Message: "Azure SQL Database orders is unsupported by azd, it needs manual provisioning"},
+	}, result.Diagnostics)
+}
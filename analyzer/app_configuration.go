@@ -0,0 +1,55 @@
+package analyzer
+
+import (
+	"fmt"
+	"strings"
+
+	"ajpa/analyzer/internal"
+)
+
+const appConfigurationStoresName = "spring.cloud.azure.appconfiguration.stores"
+
+// collectAppConfigurationStores collects the stores of spring.cloud.azure.appconfiguration.stores[*] used by Spring
+// Cloud Azure App Configuration. The endpoint of a store is from endpoint, or from the Endpoint of connection-string.
+func collectAppConfigurationStores(ctx detectionContext) Service {
+	var service AzureAppConfiguration
+	for i := 0; ; i++ {
+		prefix := fmt.Sprintf("%s[%d].", appConfigurationStoresName, i)
+		endpoint, hasEndpoint := internal.GetPropertyValue(ctx.properties, prefix+"endpoint")
+		connectionString, hasConnectionString := internal.GetPropertyValue(ctx.properties, prefix+"connection-string")
+		if !hasEndpoint && !hasConnectionString {
+			return service
+		}
+		store := AzureAppConfigurationStore{Endpoint: strings.TrimSpace(endpoint)}
+		if store.Endpoint == "" {
+			store.Endpoint = getConnectionStringEndpoint(connectionString)
+		}
+		for j := 0; ; j++ {
+			selectPrefix := fmt.Sprintf("%sselects[%d].", prefix, j)
+			keyFilter, hasKeyFilter := internal.GetPropertyValue(ctx.properties, selectPrefix+"key-filter")
+			labelFilters := internal.GetPropertyValueList(ctx.properties, selectPrefix+"label-filter")
+			if !hasKeyFilter && len(labelFilters) == 0 {
+				break
+			}
+			if keyFilter = strings.TrimSpace(keyFilter); keyFilter != "" {
+				store.KeyPrefixes = internal.AppendAndDistinctInOrder(store.KeyPrefixes, keyFilter)
+			}
+			store.LabelFilters = internal.AppendAndDistinctInOrder(store.LabelFilters, labelFilters...)
+		}
+		featureFlagsEnabled, _ := internal.GetPropertyValue(ctx.properties, prefix+"feature-flags.enabled")
+		store.FeatureFlagsEnabled = strings.TrimSpace(featureFlagsEnabled) == "true"
+		service.Stores = append(service.Stores, store)
+	}
+}
+
+// getConnectionStringEndpoint gets the endpoint from a connection string like
+// "Endpoint=https://contoso.azconfig.io;Id=...;Secret=...". Empty if not found.
+func getConnectionStringEndpoint(connectionString string) string {
+	for _, part := range strings.Split(connectionString, ";") {
+		key, value, _ := strings.Cut(strings.TrimSpace(part), "=")
+		if strings.EqualFold(key, "Endpoint") {
+			return strings.TrimSpace(value)
+		}
+	}
+	return ""
+}
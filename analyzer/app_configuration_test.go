@@ -0,0 +1,92 @@
+package analyzer
+
+import (
+	"testing"
+
+	"ajpa/analyzer/internal"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDetectAppConfiguration(t *testing.T) {
+	tests := []struct {
+		name       string
+		pomContent string
+		properties map[string]string
+		expected   map[string]Service
+	}{
+		{
+			name: "stores with selects and feature flags",
+			pomContent: `<project><dependencies><dependency><groupId>com.azure.spring</groupId>
+				<artifactId>spring-cloud-azure-appconfiguration-config-web</artifactId></dependency>
+				</dependencies></project>`,
+			properties: map[string]string{
+				"spring.cloud.azure.appconfiguration.stores[0].endpoint":                   "https://orders.azconfig.io",
+				"spring.cloud.azure.appconfiguration.stores[0].selects[0].key-filter":      "/orders/",
+				"spring.cloud.azure.appconfiguration.stores[0].selects[0].label-filter":    "prod,${SLOT:blue}",
+				"spring.cloud.azure.appconfiguration.stores[0].selects[1].key-filter":      "/common/",
+				"spring.cloud.azure.appconfiguration.stores[0].feature-flags.enabled":      "true",
+				"spring.cloud.azure.appconfiguration.stores[1].endpoint":                   "https://shared.azconfig.io",
+				"spring.cloud.azure.appconfiguration.stores[1].selects[0].label-filter[0]": "shared",
+			},
+			expected: map[string]Service{
+				DefaultAppConfigurationServiceName: AzureAppConfiguration{
+					Stores: []AzureAppConfigurationStore{
+						{
+							Endpoint:            "https://orders.azconfig.io",
+							KeyPrefixes:         []string{"/orders/", "/common/"},
+							LabelFilters:        []string{"prod", "${SLOT:blue}"},
+							FeatureFlagsEnabled: true,
+						},
+						{
+							Endpoint:     "https://shared.azconfig.io",
+							LabelFilters: []string{"shared"},
+						},
+					},
+				},
+			},
+		},
+		{
+			name:       "store connection string without dependency",
+			pomContent: "<project></project>",
+			properties: map[string]string{
+				"SPRING_CLOUD_AZURE_APPCONFIGURATION_STORES_0_CONNECTIONSTRING": "Endpoint=https://orders.azconfig.io;Id=id",
+			},
+			expected: map[string]Service{
+				DefaultAppConfigurationServiceName: AzureAppConfiguration{
+					Stores: []AzureAppConfigurationStore{{Endpoint: "https://orders.azconfig.io"}},
+				},
+			},
+		},
+		{
+			name:       "store endpoint without dependency",
+			pomContent: "<project></project>",
+			properties: map[string]string{
+				"spring.cloud.azure.appconfiguration.stores[0].endpoint": "https://orders.azconfig.io",
+			},
+			expected: map[string]Service{
+				DefaultAppConfigurationServiceName: AzureAppConfiguration{
+					Stores: []AzureAppConfigurationStore{{Endpoint: "https://orders.azconfig.io"}},
+				},
+			},
+		},
+		{
+			name: "dependency without stores",
+			pomContent: `<project><dependencies><dependency><groupId>com.azure.spring</groupId>
+				<artifactId>spring-cloud-azure-appconfiguration-config</artifactId></dependency>
+				</dependencies></project>`,
+			expected: map[string]Service{DefaultAppConfigurationServiceName: AzureAppConfiguration{}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pom, err := internal.ParseTestPom(tt.pomContent)
+			require.NoError(t, err)
+			result := ProjectAnalysisResult{}
+			require.NoError(t, addApplicationToResult(&result, "app", Application{}))
+			require.NoError(t, detectBackingServices(detectionContext{result: &result, applicationName: "app", pom: pom,
+				properties: internal.NewTestPropertyValues(tt.properties)}, loadTestDetectionRules(t)))
+			require.Equal(t, tt.expected, result.Services)
+		})
+	}
+}
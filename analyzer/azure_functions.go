@@ -0,0 +1,113 @@
+package analyzer
+
+import (
+	"slices"
+	"strings"
+
+	"ajpa/analyzer/internal"
+)
+
+// detectFunctionsBindings detects the backing services used by the trigger and binding annotations of Azure Functions:
+//   - Service Bus: queueName of @ServiceBusQueueTrigger and @ServiceBusQueueOutput, and topicName of
+//     @ServiceBusTopicTrigger and @ServiceBusTopicOutput with the subscriptionName of the trigger.
+//   - Event Hubs: eventHubName of @EventHubTrigger and @EventHubOutput, with the consumerGroup of the trigger.
+//   - Storage account: the container in path of @BlobTrigger, @BlobInput and @BlobOutput, like "images" in
+//     "images/{name}".
+//   - Cosmos DB: databaseName, and containerName or collectionName of @CosmosDBTrigger, @CosmosDBInput and
+//     @CosmosDBOutput.
+//
+// App setting references like "%QueueName%" are resolved by the app settings. The annotations are the evidence of the
+// services.
+func detectFunctionsBindings(ctx detectionContext) error {
+	properties, annotations := ctx.properties, ctx.annotations
+	addService := func(name string, service Service, annotationNames ...string) error {
+		var evidence []Evidence
+		for _, annotation := range annotations {
+			if slices.Contains(annotationNames, annotation.Name) {
+				evidence = append(evidence, newAnnotationEvidence(newAnnotation(ctx.projectRootPath, annotation)))
+			}
+		}
+		return addDetectedServiceToResult(ctx.result, ctx.applicationName,
+			DetectedService{Name: name, Service: service, Evidence: evidence})
+	}
+	getValues := func(attributeName string, annotationNames ...string) []string {
+		var values []string
+		for _, annotationName := range annotationNames {
+			values = internal.AppendAndDistinctInOrder(values, resolveFunctionsBindingValues(
+				internal.GetJavaAnnotationAttributeValues(annotations, annotationName, attributeName),
+				properties)...)
+		}
+		return values
+	}
+	var serviceBus serviceBusEntities
+	for _, queue := range getValues("queueName", "ServiceBusQueueTrigger", "ServiceBusQueueOutput") {
+		serviceBus.addQueue(queue)
+	}
+	for _, annotation := range annotations {
+		if annotation.Name != "ServiceBusTopicTrigger" && annotation.Name != "ServiceBusTopicOutput" {
+			continue
+		}
+		subscriptions := resolveFunctionsBindingValues(annotation.Attributes["subscriptionName"], properties)
+		for _, topic := range resolveFunctionsBindingValues(annotation.Attributes["topicName"], properties) {
+			serviceBus.addTopic(topic, "")
+			for _, subscription := range subscriptions {
+				serviceBus.addTopic(topic, subscription)
+			}
+		}
+	}
+	if len(serviceBus.queues) > 0 || len(serviceBus.topics) > 0 {
+		err := addService(DefaultServiceBusServiceName, serviceBus.toService(), "ServiceBusQueueTrigger",
+			"ServiceBusQueueOutput", "ServiceBusTopicTrigger", "ServiceBusTopicOutput")
+		if err != nil {
+			return err
+		}
+	}
+	var eventHubs eventHubsEntities
+	for _, annotation := range annotations {
+		if annotation.Name == "EventHubTrigger" || annotation.Name == "EventHubOutput" {
+			eventHubs.addHubs(resolveFunctionsBindingValues(annotation.Attributes["eventHubName"], properties),
+				resolveFunctionsBindingValues(annotation.Attributes["consumerGroup"], properties))
+		}
+	}
+	if len(eventHubs.hubs) > 0 {
+		err := addService(DefaultEventHubsServiceName, eventHubs.toService(), "EventHubTrigger", "EventHubOutput")
+		if err != nil {
+			return err
+		}
+	}
+	var blobContainers []string
+	for _, blobPath := range getValues("path", "BlobTrigger", "BlobInput", "BlobOutput") {
+		if container, _, _ := strings.Cut(blobPath, "/"); container != "" && !strings.Contains(container, "{") {
+			blobContainers = internal.AppendAndDistinctInOrder(blobContainers, container)
+		}
+	}
+	if len(blobContainers) > 0 {
+		err := addService(DefaultStorageServiceName, AzureStorageAccount{Containers: blobContainers},
+			"BlobTrigger", "BlobInput", "BlobOutput")
+		if err != nil {
+			return err
+		}
+	}
+	cosmosAnnotationNames := []string{"CosmosDBTrigger", "CosmosDBInput", "CosmosDBOutput"}
+	databases := getValues("databaseName", cosmosAnnotationNames...)
+	cosmosContainers := internal.AppendAndDistinctInOrder(getValues("containerName", cosmosAnnotationNames...),
+		getValues("collectionName", cosmosAnnotationNames...)...)
+	if len(databases) > 0 || len(cosmosContainers) > 0 {
+		return addService(DefaultCosmosServiceName, AzureCosmosDb{Databases: databases, Containers: cosmosContainers},
+			cosmosAnnotationNames...)
+	}
+	return nil
+}
+
+// resolveFunctionsBindingValues resolves the app setting references in the binding attribute values. Values which
+// can not be resolved are skipped.
+func resolveFunctionsBindingValues(values []string, settings internal.PropertyValues) []string {
+	var result []string
+	for _, value := range values {
+		if resolved, ok := internal.ResolveFunctionsBindingExpressions(value, settings); ok &&
+			strings.TrimSpace(resolved) != "" {
+			result = internal.AppendAndDistinctInOrder(result, strings.TrimSpace(resolved))
+		}
+	}
+	return result
+}
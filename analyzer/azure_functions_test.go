@@ -0,0 +1,108 @@
+package analyzer
+
+import (
+	"testing"
+
+	"ajpa/analyzer/internal"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDetectFunctionsBindings(t *testing.T) {
+	result := ProjectAnalysisResult{}
+	require.NoError(t, addApplicationToResult(&result, "functions", Application{}))
+	annotations := []internal.JavaAnnotation{
+		{Name: "ServiceBusQueueTrigger", Attributes: map[string][]string{
+			"name": {"message"}, "queueName": {"%OrdersQueue%"}, "connection": {"ServiceBusConnection"}}},
+		{Name: "ServiceBusQueueOutput", Attributes: map[string][]string{"queueName": {"%UnknownQueue%"}}},
+		{Name: "ServiceBusTopicOutput", Attributes: map[string][]string{"topicName": {"notifications"}}},
+		{Name: "ServiceBusTopicTrigger", Attributes: map[string][]string{
+			"topicName": {"notifications"}, "subscriptionName": {"mailer"}}},
+		{Name: "EventHubTrigger", Attributes: map[string][]string{
+			"eventHubName": {"telemetry"}, "consumerGroup": {"%TelemetryConsumerGroup%"}}},
+		{Name: "BlobTrigger", Attributes: map[string][]string{"path": {"images/{name}"}}},
+		{Name: "BlobOutput", Attributes: map[string][]string{"path": {"{container}/thumbnail.png"}}},
+		{Name: "CosmosDBTrigger", Attributes: map[string][]string{
+			"databaseName": {"shop"}, "containerName": {"orders"}}},
+		{Name: "CosmosDBInput", Attributes: map[string][]string{
+			"databaseName": {"shop"}, "collectionName": {"customers"}}},
+	}
+	properties := map[string]string{"OrdersQueue": "orders", "TelemetryConsumerGroup": "alerts"}
+	require.NoError(t, detectFunctionsBindings(detectionContext{result: &result, applicationName: "functions",
+		properties: internal.NewTestPropertyValues(properties), annotations: annotations}))
+	require.Equal(t, map[string]Service{
+		DefaultServiceBusServiceName: AzureServiceBus{
+			Queues:        []string{"orders"},
+			Topics:        []string{"notifications"},
+			Subscriptions: map[string][]string{"notifications": {"mailer"}},
+		},
+		DefaultEventHubsServiceName: AzureEventHubs{
+			Hubs:           []string{"telemetry"},
+			ConsumerGroups: map[string][]string{"telemetry": {"alerts"}},
+		},
+		DefaultStorageServiceName: AzureStorageAccount{Containers: []string{"images"}},
+		DefaultCosmosServiceName: AzureCosmosDb{
+			Databases:  []string{"shop"},
+			Containers: []string{"orders", "customers"},
+		},
+	}, result.Services)
+	require.Len(t, result.ApplicationToBackingService["functions"], 4)
+	require.Equal(t, []Evidence{
+		{Kind: EvidenceKindAnnotation, Value: "EventHubTrigger"},
+	}, result.Applications["functions"].ServiceEvidence[DefaultEventHubsServiceName])
+}
+
+func TestDetectFunctionsBindingsMergedIntoDetectedServices(t *testing.T) {
+	result := ProjectAnalysisResult{}
+	require.NoError(t, addApplicationToResult(&result, "functions", Application{}))
+	require.NoError(t, addDetectedServiceToResult(&result, "functions", DetectedService{
+		Name:    DefaultServiceBusServiceName,
+		Service: AzureServiceBus{Topics: []string{"notifications"}},
+	}))
+	require.NoError(t, addDetectedServiceToResult(&result, "functions", DetectedService{
+		Name:    DefaultEventHubsServiceName,
+		Service: AzureEventHubs{Hubs: []string{"telemetry"}},
+	}))
+	require.NoError(t, addDetectedServiceToResult(&result, "functions", DetectedService{
+		Name:    DefaultStorageServiceName,
+		Service: AzureStorageAccount{Containers: []string{"checkpoints"}},
+	}))
+	require.NoError(t, addDetectedServiceToResult(&result, "functions", DetectedService{
+		Name:    DefaultCosmosServiceName,
+		Service: AzureCosmosDb{},
+	}))
+	annotations := []internal.JavaAnnotation{
+		{Name: "ServiceBusQueueTrigger", Attributes: map[string][]string{"queueName": {"orders"}}},
+		{Name: "ServiceBusTopicTrigger", Attributes: map[string][]string{
+			"topicName": {"notifications"}, "subscriptionName": {"mailer"}}},
+		{Name: "EventHubTrigger", Attributes: map[string][]string{
+			"eventHubName": {"telemetry"}, "consumerGroup": {"alerts"}}},
+		{Name: "BlobTrigger", Attributes: map[string][]string{"path": {"images/{name}"}}},
+		{Name: "CosmosDBTrigger", Attributes: map[string][]string{
+			"databaseName": {"shop"}, "containerName": {"orders"}}},
+	}
+	require.NoError(t, detectFunctionsBindings(detectionContext{result: &result, applicationName: "functions",
+		annotations: annotations}))
+	require.Equal(t, map[string]Service{
+		DefaultServiceBusServiceName: AzureServiceBus{
+			Queues:        []string{"orders"},
+			Topics:        []string{"notifications"},
+			Subscriptions: map[string][]string{"notifications": {"mailer"}},
+		},
+		DefaultEventHubsServiceName: AzureEventHubs{
+			Hubs:           []string{"telemetry"},
+			ConsumerGroups: map[string][]string{"telemetry": {"alerts"}},
+		},
+		DefaultStorageServiceName: AzureStorageAccount{Containers: []string{"checkpoints", "images"}},
+		DefaultCosmosServiceName:  AzureCosmosDb{Databases: []string{"shop"}, Containers: []string{"orders"}},
+	}, result.Services)
+	require.Len(t, result.ApplicationToBackingService["functions"], 4)
+}
+
+func TestDetectFunctionsBindingsWithoutBindings(t *testing.T) {
+	result := ProjectAnalysisResult{}
+	require.NoError(t, addApplicationToResult(&result, "functions", Application{}))
+	require.NoError(t, detectFunctionsBindings(detectionContext{result: &result, applicationName: "functions",
+		annotations: []internal.JavaAnnotation{{Name: "HttpTrigger", Attributes: map[string][]string{"name": {"req"}}}}}))
+	require.Nil(t, result.Services)
+}
@@ -0,0 +1,179 @@
+package analyzer
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"ajpa/analyzer/internal"
+)
+
+// openAiClient is a Spring AI client of (Azure) OpenAI, and the properties configuring its models.
+type openAiClient struct {
+	dependencies []string
+	models       []openAiModelOptions
+}
+
+type openAiModelOptions struct {
+	// enabledProperty disables the model when it's "false".
+	enabledProperty string
+	// deploymentNameProperty is the Azure OpenAI deployment, which is used as the service name. It's empty for OpenAI,
+	// whose service name is the model name.
+	deploymentNameProperty string
+	modelProperty          string
+	// defaultModel is used when neither the deployment name nor the model is set. Spring AI also uses it as the
+	// default deployment name of Azure OpenAI.
+	defaultModel string
+}
+
+var openAiClients = []openAiClient{
+	{
+		dependencies: []string{
+			"org.springframework.ai:spring-ai-azure-openai-spring-boot-starter",
+			"org.springframework.ai:spring-ai-starter-model-azure-openai",
+			"org.springframework.ai:spring-ai-azure-openai",
+		},
+		models: []openAiModelOptions{
+			{
+				enabledProperty:        "spring.ai.azure.openai.chat.enabled",
+				deploymentNameProperty: "spring.ai.azure.openai.chat.options.deployment-name",
+				modelProperty:          "spring.ai.azure.openai.chat.options.model",
+				defaultModel:           "gpt-4o",
+			},
+			{
+				enabledProperty:        "spring.ai.azure.openai.embedding.enabled",
+				deploymentNameProperty: "spring.ai.azure.openai.embedding.options.deployment-name",
+				modelProperty:          "spring.ai.azure.openai.embedding.options.model",
+				defaultModel:           "text-embedding-ada-002",
+			},
+		},
+	},
+	{
+		dependencies: []string{
+			"org.springframework.ai:spring-ai-openai-spring-boot-starter",
+			"org.springframework.ai:spring-ai-starter-model-openai",
+			"org.springframework.ai:spring-ai-openai",
+		},
+		models: []openAiModelOptions{
+			{
+				enabledProperty: "spring.ai.openai.chat.enabled",
+				modelProperty:   "spring.ai.openai.chat.options.model",
+				defaultModel:    "gpt-4o-mini",
+			},
+			{
+				enabledProperty: "spring.ai.openai.embedding.enabled",
+				modelProperty:   "spring.ai.openai.embedding.options.model",
+				defaultModel:    "text-embedding-ada-002",
+			},
+		},
+	},
+}
+
+// openAiModelVersions are the versions of the known models, used when deploying them.
+var openAiModelVersions = map[string]string{
+	"gpt-4o":                 "2024-08-06",
+	"gpt-4o-mini":            "2024-07-18",
+	"gpt-4":                  "turbo-2024-04-09",
+	"gpt-35-turbo":           "0125",
+	"text-embedding-ada-002": "2",
+	"text-embedding-3-small": "1",
+	"text-embedding-3-large": "1",
+}
+
+// detectOpenAiModels detects the models used by the Spring AI clients of Azure OpenAI and OpenAI, one AzureOpenAiModel
+// per distinct service name. The service name is the Azure OpenAI deployment name if it's set, otherwise the model
+// name. The deployment name is chosen by the user, so the model is only known from the model property or the default
+// model. A warning diagnostic is added if the model or its version is unknown, which must be set manually. The
+// Azure OpenAI SDK (com.azure:azure-ai-openai) chooses models in code, so an info diagnostic is added if it's used
+// without any Spring AI client.
+//
+// Unlike the detection rules, which add one service of a fixed name per rule, each client adds a service per model
+// named by property values, so the models are detected in code like the datasources.
+func detectOpenAiModels(ctx detectionContext) error {
+	detected := false
+	for _, client := range openAiClients {
+		var dependencyEvidence []Evidence
+		for _, dependency := range client.dependencies {
+			groupId, artifactId, _ := strings.Cut(dependency, ":")
+			if hasDependency(ctx.pom, groupId, artifactId) {
+				dependencyEvidence = append(dependencyEvidence,
+					Evidence{Kind: EvidenceKindDependency, Value: dependency, FilePath: ctx.pom.PomFilePath})
+			}
+		}
+		if len(dependencyEvidence) == 0 {
+			continue
+		}
+		detected = true
+		for _, options := range client.models {
+			enabled, _ := internal.GetPropertyValue(ctx.properties, options.enabledProperty)
+			if strings.TrimSpace(enabled) == "false" {
+				continue
+			}
+			evidence := slices.Clone(dependencyEvidence)
+			getProperty := func(name string) string {
+				if name == "" {
+					return ""
+				}
+				value, _ := internal.GetPropertyValue(ctx.properties, name)
+				if value = strings.TrimSpace(value); value != "" {
+					evidence = append(evidence, newPropertyEvidence(
+						ctx.result.Applications[ctx.applicationName].Properties, name))
+				}
+				return value
+			}
+			deploymentName, model := getProperty(options.deploymentNameProperty), getProperty(options.modelProperty)
+			if deploymentName == "" && model == "" {
+				model = options.defaultModel
+			}
+			name := deploymentName
+			if name == "" {
+				name = model
+			}
+			version := openAiModelVersions[model]
+			if diagnostic, ok := newOpenAiModelDiagnostic(ctx, name, model, version, options); ok {
+				ctx.result.Diagnostics = append(ctx.result.Diagnostics, diagnostic)
+			}
+			err := addDetectedServiceToResult(ctx.result, ctx.applicationName, DetectedService{
+				Name:     name,
+				Service:  AzureOpenAiModel{Name: model, Version: version},
+				Evidence: evidence,
+			})
+			if err != nil {
+				return err
+			}
+		}
+	}
+	if !detected && hasDependency(ctx.pom, "com.azure", "azure-ai-openai") {
+		ctx.result.Diagnostics = append(ctx.result.Diagnostics, Diagnostic{
+			Severity:        DiagnosticSeverityInfo,
+			ApplicationName: ctx.applicationName,
+			FilePath:        ctx.pom.PomFilePath,
+			Message: "com.azure:azure-ai-openai chooses the models in code, add the models used as ai.openai.model " +
+				"resources manually",
+		})
+	}
+	return nil
+}
+
+// newOpenAiModelDiagnostic warns about the unknown model or model version of the service, false is returned if both
+// are known.
+func newOpenAiModelDiagnostic(ctx detectionContext, name string, model string, version string,
+	options openAiModelOptions) (Diagnostic, bool) {
+	var message string
+	switch {
+	case model == "":
+		message = fmt.Sprintf("the model of Azure OpenAI deployment %s is unknown, set %s or the model of the "+
+			"ai.openai.model resource manually", name, options.modelProperty)
+	case version == "":
+		message = fmt.Sprintf("the version of model %s is unknown, set the model version of the ai.openai.model "+
+			"resource %s manually", model, name)
+	default:
+		return Diagnostic{}, false
+	}
+	return Diagnostic{
+		Severity:        DiagnosticSeverityWarning,
+		ApplicationName: ctx.applicationName,
+		FilePath:        ctx.pom.PomFilePath,
+		Message:         message,
+	}, true
+}
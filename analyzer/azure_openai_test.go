@@ -0,0 +1,107 @@
+package analyzer
+
+import (
+	"strings"
+	"testing"
+
+	"ajpa/analyzer/internal"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDetectOpenAiModels(t *testing.T) {
+	tests := []struct {
+		name                string
+		artifacts           []string
+		properties          map[string]string
+		expected            map[string]Service
+		expectedDiagnostics int
+	}{
+		{
+			name:      "azure openai starter with deployment names and models",
+			artifacts: []string{"org.springframework.ai:spring-ai-azure-openai-spring-boot-starter"},
+			properties: map[string]string{
+				"spring.ai.azure.openai.chat.options.deployment-name":     "orders-assistant",
+				"spring.ai.azure.openai.chat.options.model":               "gpt-4o",
+				"SPRING_AI_AZURE_OPENAI_EMBEDDING_OPTIONS_DEPLOYMENTNAME": "text-embedding-3-small",
+				"SPRING_AI_AZURE_OPENAI_EMBEDDING_OPTIONS_MODEL":          "text-embedding-3-small",
+			},
+			expected: map[string]Service{
+				"orders-assistant":       AzureOpenAiModel{Name: "gpt-4o", Version: "2024-08-06"},
+				"text-embedding-3-small": AzureOpenAiModel{Name: "text-embedding-3-small", Version: "1"},
+			},
+		},
+		{
+			name:      "azure openai starter with deployment name only",
+			artifacts: []string{"org.springframework.ai:spring-ai-azure-openai-spring-boot-starter"},
+			properties: map[string]string{
+				"spring.ai.azure.openai.chat.options.deployment-name": "orders-assistant",
+				"spring.ai.azure.openai.embedding.enabled":            "false",
+			},
+			expected: map[string]Service{
+				"orders-assistant": AzureOpenAiModel{},
+			},
+			expectedDiagnostics: 1,
+		},
+		{
+			name:      "azure openai starter with model and defaults",
+			artifacts: []string{"org.springframework.ai:spring-ai-azure-openai-spring-boot-starter"},
+			properties: map[string]string{
+				"spring.ai.azure.openai.chat.options.model": "o1",
+			},
+			expected: map[string]Service{
+				"o1":                     AzureOpenAiModel{Name: "o1"},
+				"text-embedding-ada-002": AzureOpenAiModel{Name: "text-embedding-ada-002", Version: "2"},
+			},
+			expectedDiagnostics: 1,
+		},
+		{
+			name:      "openai with disabled embedding",
+			artifacts: []string{"org.springframework.ai:spring-ai-openai"},
+			properties: map[string]string{
+				"spring.ai.openai.chat.options.model":      "gpt-4o",
+				"spring.ai.openai.embedding.enabled":       "false",
+				"spring.ai.openai.embedding.options.model": "text-embedding-3-large",
+			},
+			expected: map[string]Service{
+				"gpt-4o": AzureOpenAiModel{Name: "gpt-4o", Version: "2024-08-06"},
+			},
+		},
+		{
+			name: "same model of azure openai and openai",
+			artifacts: []string{"org.springframework.ai:spring-ai-azure-openai-spring-boot-starter",
+				"org.springframework.ai:spring-ai-openai"},
+			properties: map[string]string{
+				"spring.ai.azure.openai.embedding.enabled": "false",
+				"spring.ai.openai.chat.options.model":      "gpt-4o",
+				"spring.ai.openai.embedding.enabled":       "false",
+			},
+			expected: map[string]Service{
+				"gpt-4o": AzureOpenAiModel{Name: "gpt-4o", Version: "2024-08-06"},
+			},
+		},
+		{
+			name:                "azure openai sdk",
+			artifacts:           []string{"com.azure:azure-ai-openai"},
+			expectedDiagnostics: 1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pomContent := "<project><dependencies>"
+			for _, artifact := range tt.artifacts {
+				groupId, artifactId, _ := strings.Cut(artifact, ":")
+				pomContent += "<dependency><groupId>" + groupId + "</groupId><artifactId>" + artifactId +
+					"</artifactId></dependency>"
+			}
+			pom, err := internal.ParseTestPom(pomContent + "</dependencies></project>")
+			require.NoError(t, err)
+			result := ProjectAnalysisResult{}
+			require.NoError(t, addApplicationToResult(&result, "app", Application{}))
+			require.NoError(t, detectOpenAiModels(detectionContext{result: &result, applicationName: "app", pom: pom,
+				properties: internal.NewTestPropertyValues(tt.properties)}))
+			require.Equal(t, tt.expected, result.Services)
+			require.Len(t, result.Diagnostics, tt.expectedDiagnostics)
+		})
+	}
+}
@@ -0,0 +1,125 @@
+package analyzer
+
+import (
+	"slices"
+
+	"ajpa/analyzer/internal"
+)
+
+// datasourceURLPropertyNames are the properties of datasource URLs, in the order of precedence of the services named
+// by the default service names. "*" matches any characters, like "spring.datasource.*.url" matching
+// "spring.datasource.secondary.url".
+var datasourceURLPropertyNames = []string{
+	"spring.datasource.url",
+	"spring.r2dbc.url",
+	"quarkus.datasource.jdbc.url",
+	"quarkus.datasource.reactive.url",
+	"datasources.default.url",
+	"spring.datasource.*.url",
+	"spring.datasource.*.jdbc-url",
+	"app.datasource.url",
+	"app.datasource.*.url",
+	"quarkus.datasource.*.jdbc.url",
+	"quarkus.datasource.*.reactive.url",
+	"datasources.*.url",
+	"spring.flyway.url",
+	"spring.liquibase.url",
+}
+
+// datasourceServiceTypes maps the vendors of internal.DatasourceURL to the service types of detectionServiceTypes.
+var datasourceServiceTypes = map[string]string{
+	"postgresql": "postgresql",
+	"mysql":      "mysql",
+	"mariadb":    "mariadb",
+	"sqlserver":  "sql-server",
+}
+
+// getDatasources gets the datasources of the URL properties which can be parsed, see internal.ParseDatasourceURL.
+func getDatasources(properties internal.PropertyValues) []Datasource {
+	var urlProperties []internal.PropertyValue
+	for _, pattern := range datasourceURLPropertyNames {
+		for _, property := range getMatchedProperties(properties, pattern) {
+			if !slices.ContainsFunc(urlProperties, func(p internal.PropertyValue) bool {
+				return p.Name == property.Name
+			}) {
+				urlProperties = append(urlProperties, property)
+			}
+		}
+	}
+	var result []Datasource
+	for _, property := range urlProperties {
+		url, ok := internal.ParseDatasourceURL(property.Value)
+		if !ok {
+			continue
+		}
+		result = append(result, Datasource{
+			PropertyName: property.Name,
+			Vendor:       url.Vendor,
+			Host:         url.Host,
+			Port:         url.Port,
+			DatabaseName: url.DatabaseName,
+		})
+	}
+	return result
+}
+
+// detectDatasources detects a database service for each distinct database of the datasource URLs. The first database
+// of a vendor is named by the default service name like DefaultPostgresqlServiceName, and the others are suffixed by
+// their database names like "postgresql-inventory". Database names which are not valid, like unresolved placeholders,
+// are treated as the first database of the vendor. Embedded databases like H2 and unsupported databases like Oracle
+// don't have services. The datasources are added to Application.Datasources. The names are chosen per module, so
+// different databases of other modules named the same are renamed when merging, see renameConflictingDatabaseServices.
+//
+// The detection rules of the database drivers only apply if no datasource URL is set, see the builtin condition
+// "no-datasource-url".
+func detectDatasources(ctx detectionContext) error {
+	datasources := getDatasources(ctx.properties)
+	if len(datasources) == 0 {
+		return nil
+	}
+	serviceNames := make(map[string]string)      // vendor + database name -> service name
+	firstServiceNames := make(map[string]string) // vendor -> service name of the first database
+	for i, datasource := range datasources {
+		serviceType, ok := datasourceServiceTypes[datasource.Vendor]
+		if !ok {
+			continue
+		}
+		databaseName := datasource.DatabaseName
+		if !internal.IsValidDatabaseName(databaseName) {
+			databaseName = ""
+		}
+		key := datasource.Vendor + "/" + databaseName
+		name, ok := serviceNames[key]
+		if !ok {
+			firstName, hasFirst := firstServiceNames[datasource.Vendor]
+			switch {
+			case !hasFirst:
+				name = detectionServiceTypes[serviceType].defaultName
+				firstServiceNames[datasource.Vendor] = name
+			case databaseName == "":
+				name = firstName
+			default:
+				name = detectionServiceTypes[serviceType].defaultName + "-" + databaseName
+			}
+			serviceNames[key] = name
+		}
+		var fields map[string][]string
+		if databaseName != "" {
+			fields = map[string][]string{"databaseName": {databaseName}}
+		}
+		err := addDetectedServiceToResult(ctx.result, ctx.applicationName, DetectedService{
+			Name:    name,
+			Service: detectionServiceTypes[serviceType].newService(fields),
+			Evidence: []Evidence{newPropertyEvidence(ctx.result.Applications[ctx.applicationName].Properties,
+				datasource.PropertyName)},
+		})
+		if err != nil {
+			return err
+		}
+		datasources[i].ServiceName = name
+	}
+	application := ctx.result.Applications[ctx.applicationName]
+	application.Datasources = datasources
+	ctx.result.Applications[ctx.applicationName] = application
+	return nil
+}
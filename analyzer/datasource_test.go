@@ -0,0 +1,129 @@
+package analyzer
+
+import (
+	"strings"
+	"testing"
+
+	"ajpa/analyzer/internal"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDetectDatasources(t *testing.T) {
+	tests := []struct {
+		name       string
+		artifacts  []string
+		properties map[string]string
+		expected   map[string]Service
+	}{
+		{
+			name:       "spring datasource",
+			artifacts:  []string{"org.postgresql:postgresql"},
+			properties: map[string]string{"spring.datasource.url": "jdbc:postgresql://localhost:5432/petclinic"},
+			expected: map[string]Service{
+				DefaultPostgresqlServiceName: AzureDatabaseForPostgresql{DatabaseName: "petclinic"},
+			},
+		},
+		{
+			name:       "h2 datasource with postgresql driver",
+			artifacts:  []string{"org.postgresql:postgresql"},
+			properties: map[string]string{"spring.datasource.url": "jdbc:h2:mem:petclinic"},
+		},
+		{
+			name:       "r2dbc without driver",
+			properties: map[string]string{"SPRING_R2DBC_URL": "r2dbc:mysql://localhost:3306/inventory"},
+			expected:   map[string]Service{DefaultMysqlServiceName: AzureDatabaseForMysql{DatabaseName: "inventory"}},
+		},
+		{
+			name:      "datasource url takes precedence over micronaut data dialect",
+			artifacts: []string{"io.micronaut.data:micronaut-data-jdbc"},
+			properties: map[string]string{
+				"datasources.default.dialect": "MYSQL",
+				"datasources.default.url":     "jdbc:postgresql://localhost:5432/inventory",
+			},
+			expected: map[string]Service{
+				DefaultPostgresqlServiceName: AzureDatabaseForPostgresql{DatabaseName: "inventory"},
+			},
+		},
+		{
+			name: "sql server and mariadb",
+			properties: map[string]string{
+				"spring.datasource.url":              "jdbc:sqlserver://localhost:1433;databaseName=orders",
+				"app.datasource.inventory.url":       "jdbc:mariadb://localhost:3306/inventory",
+				"quarkus.datasource.legacy.jdbc.url": "jdbc:oracle:thin:@//localhost:1521/legacy",
+			},
+			expected: map[string]Service{
+				DefaultSqlServerServiceName: AzureSqlDatabase{DatabaseName: "orders"},
+				DefaultMariadbServiceName:   AzureDatabaseForMariadb{DatabaseName: "inventory"},
+			},
+		},
+		{
+			name:       "unresolved database name",
+			properties: map[string]string{"spring.datasource.url": "jdbc:postgresql://${DB_HOST}:5432/${DB_NAME}"},
+			expected:   map[string]Service{DefaultPostgresqlServiceName: AzureDatabaseForPostgresql{}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pomContent := "<project><dependencies>"
+			for _, artifact := range tt.artifacts {
+				groupId, artifactId, _ := strings.Cut(artifact, ":")
+				pomContent += "<dependency><groupId>" + groupId + "</groupId><artifactId>" + artifactId +
+					"</artifactId></dependency>"
+			}
+			pom, err := internal.ParseTestPom(pomContent + "</dependencies></project>")
+			require.NoError(t, err)
+			result := ProjectAnalysisResult{}
+			require.NoError(t, addApplicationToResult(&result, "app", Application{}))
+			ctx := detectionContext{result: &result, applicationName: "app", pom: pom,
+				properties: internal.NewTestPropertyValues(tt.properties)}
+			require.NoError(t, detectDatasources(ctx))
+			require.NoError(t, detectBackingServices(ctx, loadTestDetectionRules(t)))
+			require.Equal(t, tt.expected, result.Services)
+		})
+	}
+}
+
+func TestDetectDatasourcesOfMultipleDatabases(t *testing.T) {
+	properties := map[string]string{
+		"spring.datasource.url":           "jdbc:postgresql://orders-db:5432/orders",
+		"spring.datasource.reporting.url": "jdbc:postgresql://reporting-db/reporting",
+		"spring.flyway.url":               "jdbc:postgresql://orders-db:5432/orders",
+	}
+	result := ProjectAnalysisResult{}
+	require.NoError(t, addApplicationToResult(&result, "app", Application{
+		Properties: map[string]PropertyValue{
+			"spring.datasource.url": {Name: "spring.datasource.url", Value: properties["spring.datasource.url"],
+				Origin: PropertyOrigin{FilePath: "application.properties", Line: 1}},
+			"spring.datasource.reporting.url": {Name: "spring.datasource.reporting.url",
+				Value:  properties["spring.datasource.reporting.url"],
+				Origin: PropertyOrigin{FilePath: "application.properties", Line: 2}},
+			"spring.flyway.url": {Name: "spring.flyway.url", Value: properties["spring.flyway.url"],
+				Origin: PropertyOrigin{FilePath: "application.properties", Line: 3}},
+		},
+	}))
+	require.NoError(t, detectDatasources(detectionContext{result: &result, applicationName: "app",
+		properties: internal.NewTestPropertyValues(properties)}))
+	require.Equal(t, map[string]Service{
+		DefaultPostgresqlServiceName: AzureDatabaseForPostgresql{DatabaseName: "orders"},
+		"postgresql-reporting":       AzureDatabaseForPostgresql{DatabaseName: "reporting"},
+	}, result.Services)
+	require.Equal(t, []Datasource{
+		{PropertyName: "spring.datasource.url", Vendor: "postgresql", Host: "orders-db", Port: 5432,
+			DatabaseName: "orders", ServiceName: DefaultPostgresqlServiceName},
+		{PropertyName: "spring.datasource.reporting.url", Vendor: "postgresql", Host: "reporting-db",
+			DatabaseName: "reporting", ServiceName: "postgresql-reporting"},
+		{PropertyName: "spring.flyway.url", Vendor: "postgresql", Host: "orders-db", Port: 5432,
+			DatabaseName: "orders", ServiceName: DefaultPostgresqlServiceName},
+	}, result.Applications["app"].Datasources)
+	require.Equal(t, map[string][]Evidence{
+		DefaultPostgresqlServiceName: {
+			{Kind: EvidenceKindProperty, Value: "spring.datasource.url", FilePath: "application.properties", Line: 1},
+			{Kind: EvidenceKindProperty, Value: "spring.flyway.url", FilePath: "application.properties", Line: 3},
+		},
+		"postgresql-reporting": {
+			{Kind: EvidenceKindProperty, Value: "spring.datasource.reporting.url", FilePath: "application.properties",
+				Line: 2},
+		},
+	}, result.Applications["app"].ServiceEvidence)
+}
@@ -0,0 +1,437 @@
+package analyzer
+
+import (
+	"bytes"
+	_ "embed"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+
+	"ajpa/analyzer/internal"
+
+	"github.com/braydonk/yaml"
+)
+
+//go:embed detection_rules.yaml
+var embeddedDetectionRules []byte
+
+// detectionRules is the content of detection_rules.yaml and of the file in AnalyzeOptions.DetectionRulesPath.
+type detectionRules struct {
+	Rules []detectionRule `yaml:"rules"`
+}
+
+// detectionRule adds a backing service of ServiceType to the application if any condition in AnyOf matches. The
+// fields of the service are extracted from the properties by Fields, or the whole service is built by Collector for
+// services which can not be described by properties, like the queues and topics of Service Bus.
+type detectionRule struct {
+	// Id identifies the rule. Rules in the user rules file replace the embedded rules having the same id.
+	Id string `yaml:"id"`
+	// Disabled removes the embedded rule having the same id.
+	Disabled    bool   `yaml:"disabled"`
+	ServiceType string `yaml:"serviceType"`
+	// ServiceName defaults to the default name of the service type, like DefaultPostgresqlServiceName.
+	ServiceName string               `yaml:"serviceName"`
+	AnyOf       []detectionCondition `yaml:"anyOf"`
+	Fields      []detectionField     `yaml:"fields"`
+	Collector   string               `yaml:"collector"`
+}
+
+// detectionCondition matches if every criterion set in it matches. Lists match if any item in them matches.
+type detectionCondition struct {
+	// Dependencies are like "org.postgresql:postgresql".
+	Dependencies []string `yaml:"dependencies"`
+	// Plugins are like "org.springframework.boot:spring-boot-maven-plugin". Gradle plugins are matched by their plugin
+	// marker coordinates, like "io.quarkus:io.quarkus.gradle.plugin".
+	Plugins    []string          `yaml:"plugins"`
+	Properties []propertyMatcher `yaml:"properties"`
+	// AbsentProperties are the property names which must not be set.
+	AbsentProperties []string `yaml:"absentProperties"`
+	// Annotations are simple names like "JmsListener".
+	Annotations []string `yaml:"annotations"`
+	// Builtin is a condition implemented in code, see detectionBuiltinConditions.
+	Builtin string `yaml:"builtin"`
+}
+
+// propertyMatcher matches a set property whose value equals any of Values or starts with any of Prefixes, ignoring
+// case. If neither is set, any value matches. Name follows relaxed binding, and "*" in it matches any characters, like
+// "spring.cloud.azure.keyvault.secret.property-sources[*].endpoint".
+type propertyMatcher struct {
+	Name     string   `yaml:"name"`
+	Values   []string `yaml:"values"`
+	Prefixes []string `yaml:"prefixes"`
+}
+
+// detectionField extracts a field of the service from the values of Properties, whose names are the same as
+// propertyMatcher.Name. If When is set, the field is only extracted if any condition in it matches.
+type detectionField struct {
+	Name       string               `yaml:"name"`
+	Properties []string             `yaml:"properties"`
+	Format     string               `yaml:"format"`
+	When       []detectionCondition `yaml:"when"`
+}
+
+// detectionContext is the module the rules are applied to.
+type detectionContext struct {
+	result          *ProjectAnalysisResult
+	applicationName string
+	projectRootPath string
+	// projectPath is the directory having the build file.
+	projectPath string
+	pom         internal.Pom
+	properties  internal.PropertyValues
+	annotations []internal.JavaAnnotation
+}
+
+// detectionServiceType creates the service from the extracted fields, field name -> distinct values in order.
+type detectionServiceType struct {
+	defaultName string
+	newService  func(fields map[string][]string) Service
+}
+
+var detectionServiceTypes = map[string]detectionServiceType{
+	"postgresql": {DefaultPostgresqlServiceName, func(fields map[string][]string) Service {
+		return AzureDatabaseForPostgresql{DatabaseName: firstFieldValue(fields, "databaseName")}
+	}},
+	"mysql": {DefaultMysqlServiceName, func(fields map[string][]string) Service {
+		return AzureDatabaseForMysql{DatabaseName: firstFieldValue(fields, "databaseName")}
+	}},
+	"sql-server": {DefaultSqlServerServiceName, func(fields map[string][]string) Service {
+		return AzureSqlDatabase{DatabaseName: firstFieldValue(fields, "databaseName")}
+	}},
+	"mariadb": {DefaultMariadbServiceName, func(fields map[string][]string) Service {
+		return AzureDatabaseForMariadb{DatabaseName: firstFieldValue(fields, "databaseName")}
+	}},
+	"redis": {DefaultRedisServiceName, func(fields map[string][]string) Service {
+		return AzureCacheForRedis{}
+	}},
+	"mongo": {DefaultMongoServiceName, func(fields map[string][]string) Service {
+		return AzureCosmosDbForMongoDb{}
+	}},
+	"cosmos": {DefaultCosmosServiceName, func(fields map[string][]string) Service {
+		return AzureCosmosDb{Databases: fields["databases"], Containers: fields["containers"]}
+	}},
+	"service-bus": {DefaultServiceBusServiceName, func(fields map[string][]string) Service {
+		return AzureServiceBus{Queues: fields["queues"], Topics: fields["topics"]}
+	}},
+	"event-hubs": {DefaultEventHubsServiceName, func(fields map[string][]string) Service {
+		return AzureEventHubs{Hubs: fields["hubs"]}
+	}},
+	"storage": {DefaultStorageServiceName, func(fields map[string][]string) Service {
+		return AzureStorageAccount{Containers: fields["containers"]}
+	}},
+	"key-vault": {DefaultKeyVaultServiceName, func(fields map[string][]string) Service {
+		return AzureKeyVault{Endpoints: fields["endpoints"]}
+	}},
+	"app-configuration": {DefaultAppConfigurationServiceName, func(fields map[string][]string) Service {
+		return AzureAppConfiguration{}
+	}},
+}
+
+// detectionCollectors build the services which can not be described by fields.
+var detectionCollectors = map[string]func(ctx detectionContext) Service{
+	"service-bus-entities":     collectServiceBusEntities,
+	"event-hubs-entities":      collectEventHubsEntities,
+	"app-configuration-stores": collectAppConfigurationStores,
+}
+
+// detectionBuiltinConditions are the conditions which can not be described by dependencies and properties.
+var detectionBuiltinConditions = map[string]func(ctx detectionContext) bool{
+	"kafka-on-event-hubs":  detectKafkaOnEventHubs,
+	"stream-input-binding": func(ctx detectionContext) bool { return containsInKeywordInBindingName(ctx.properties) },
+	"no-datasource-url":    func(ctx detectionContext) bool { return len(getDatasources(ctx.properties)) == 0 },
+}
+
+// detectionFieldFormats convert the property values. Empty results are ignored.
+var detectionFieldFormats = map[string]func(value string) string{
+	"":              strings.TrimSpace,
+	"database-name": internal.GetDatabaseName,
+}
+
+var loadEmbeddedDetectionRules = sync.OnceValues(func() ([]detectionRule, error) {
+	return parseDetectionRules("detection_rules.yaml", embeddedDetectionRules)
+})
+
+// loadDetectionRules loads the embedded rules, then applies the rules in the user rules file if userRulesPath is set.
+// A user rule replaces the embedded rule having the same id, or is added after the embedded rules.
+func loadDetectionRules(userRulesPath string) ([]detectionRule, error) {
+	rules, err := loadEmbeddedDetectionRules()
+	if err != nil {
+		return nil, err
+	}
+	if userRulesPath == "" {
+		return rules, nil
+	}
+	data, err := os.ReadFile(userRulesPath)
+	if err != nil {
+		return nil, fmt.Errorf("reading detection rules: %w", err)
+	}
+	userRules, err := parseDetectionRules(userRulesPath, data)
+	if err != nil {
+		return nil, err
+	}
+	rules = slices.Clone(rules)
+	for _, userRule := range userRules {
+		index := slices.IndexFunc(rules, func(rule detectionRule) bool { return rule.Id == userRule.Id })
+		switch {
+		case index >= 0 && userRule.Disabled:
+			rules = slices.Delete(rules, index, index+1)
+		case index >= 0:
+			rules[index] = userRule
+		case !userRule.Disabled:
+			rules = append(rules, userRule)
+		}
+	}
+	return rules, nil
+}
+
+func parseDetectionRules(filePath string, data []byte) ([]detectionRule, error) {
+	var rules detectionRules
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(&rules); err != nil && !errors.Is(err, io.EOF) {
+		return nil, &internal.ConfigFileError{FilePath: filePath, Err: err}
+	}
+	for _, rule := range rules.Rules {
+		if err := validateDetectionRule(rule); err != nil {
+			return nil, &internal.ConfigFileError{FilePath: filePath, Err: err}
+		}
+	}
+	return rules.Rules, nil
+}
+
+func validateDetectionRule(rule detectionRule) error {
+	if rule.Id == "" {
+		return errors.New("detection rule without id")
+	}
+	if rule.Disabled {
+		return nil
+	}
+	if _, ok := detectionServiceTypes[rule.ServiceType]; !ok {
+		return fmt.Errorf("detection rule %s: unknown service type %q", rule.Id, rule.ServiceType)
+	}
+	if _, ok := detectionCollectors[rule.Collector]; rule.Collector != "" && !ok {
+		return fmt.Errorf("detection rule %s: unknown collector %q", rule.Id, rule.Collector)
+	}
+	if len(rule.AnyOf) == 0 {
+		return fmt.Errorf("detection rule %s: no condition in anyOf", rule.Id)
+	}
+	conditions := slices.Clone(rule.AnyOf)
+	for _, field := range rule.Fields {
+		if _, ok := detectionFieldFormats[field.Format]; !ok {
+			return fmt.Errorf("detection rule %s: unknown format %q of field %s", rule.Id, field.Format, field.Name)
+		}
+		conditions = append(conditions, field.When...)
+	}
+	for _, condition := range conditions {
+		if _, ok := detectionBuiltinConditions[condition.Builtin]; condition.Builtin != "" && !ok {
+			return fmt.Errorf("detection rule %s: unknown builtin condition %q", rule.Id, condition.Builtin)
+		}
+		for _, coordinates := range append(slices.Clone(condition.Dependencies), condition.Plugins...) {
+			if _, _, ok := strings.Cut(coordinates, ":"); !ok {
+				return fmt.Errorf("detection rule %s: %q is not like groupId:artifactId", rule.Id, coordinates)
+			}
+		}
+	}
+	return nil
+}
+
+// detectBackingServices applies the rules in order, and adds the services of the matched rules to the application,
+// with the dependencies, plugins, properties and annotations matched by the conditions as the evidence.
+func detectBackingServices(ctx detectionContext, rules []detectionRule) error {
+	for _, rule := range rules {
+		// All conditions are evaluated, because builtin conditions may add diagnostics.
+		matched := false
+		var evidence []Evidence
+		for _, condition := range rule.AnyOf {
+			if conditionEvidence, ok := condition.match(ctx); ok {
+				matched = true
+				evidence = append(evidence, conditionEvidence...)
+			}
+		}
+		if !matched {
+			continue
+		}
+		serviceType := detectionServiceTypes[rule.ServiceType]
+		detectedService := DetectedService{Name: rule.ServiceName, Evidence: evidence}
+		if detectedService.Name == "" {
+			detectedService.Name = serviceType.defaultName
+		}
+		if rule.Collector != "" {
+			detectedService.Service = detectionCollectors[rule.Collector](ctx)
+		} else {
+			detectedService.Service = serviceType.newService(extractDetectionFields(ctx, rule.Fields))
+		}
+		if err := addDetectedServiceToResult(ctx.result, ctx.applicationName, detectedService); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// match checks whether the condition matches, and gets the evidence of the matched criteria.
+func (c detectionCondition) match(ctx detectionContext) ([]Evidence, bool) {
+	if len(c.Dependencies) == 0 && len(c.Plugins) == 0 && len(c.Properties) == 0 && len(c.AbsentProperties) == 0 &&
+		len(c.Annotations) == 0 && c.Builtin == "" {
+		return nil, false
+	}
+	var result []Evidence
+	matchAny := func(candidates []string, getEvidence func(candidate string) []Evidence) bool {
+		if len(candidates) == 0 {
+			return true
+		}
+		var evidence []Evidence
+		for _, candidate := range candidates {
+			evidence = append(evidence, getEvidence(candidate)...)
+		}
+		result = append(result, evidence...)
+		return len(evidence) > 0
+	}
+	matched := matchAny(c.Dependencies, func(coordinates string) []Evidence {
+		groupId, artifactId, _ := strings.Cut(coordinates, ":")
+		if !hasDependency(ctx.pom, groupId, artifactId) {
+			return nil
+		}
+		return []Evidence{{Kind: EvidenceKindDependency, Value: coordinates, FilePath: ctx.pom.PomFilePath}}
+	}) && matchAny(c.Plugins, func(coordinates string) []Evidence {
+		groupId, artifactId, _ := strings.Cut(coordinates, ":")
+		if !hasPlugin(ctx.pom, groupId, artifactId) {
+			return nil
+		}
+		return []Evidence{{Kind: EvidenceKindPlugin, Value: coordinates, FilePath: ctx.pom.PomFilePath}}
+	})
+	if !matched {
+		return nil, false
+	}
+	if len(c.Properties) > 0 {
+		var propertyEvidence []Evidence
+		for _, matcher := range c.Properties {
+			for _, property := range matcher.match(ctx.properties) {
+				propertyEvidence = append(propertyEvidence, newPropertyEvidence(
+					ctx.result.Applications[ctx.applicationName].Properties, property.Name))
+			}
+		}
+		if len(propertyEvidence) == 0 {
+			return nil, false
+		}
+		result = append(result, propertyEvidence...)
+	}
+	if slices.ContainsFunc(c.AbsentProperties, func(name string) bool {
+		return len(getMatchedProperties(ctx.properties, name)) > 0
+	}) {
+		return nil, false
+	}
+	if len(c.Annotations) > 0 {
+		var annotationEvidence []Evidence
+		for _, name := range c.Annotations {
+			index := slices.IndexFunc(ctx.annotations, func(annotation internal.JavaAnnotation) bool {
+				return annotation.Name == name
+			})
+			if index >= 0 {
+				annotationEvidence = append(annotationEvidence,
+					newAnnotationEvidence(newAnnotation(ctx.projectRootPath, ctx.annotations[index])))
+			}
+		}
+		if len(annotationEvidence) == 0 {
+			return nil, false
+		}
+		result = append(result, annotationEvidence...)
+	}
+	if c.Builtin != "" {
+		if !detectionBuiltinConditions[c.Builtin](ctx) {
+			return nil, false
+		}
+		result = append(result, Evidence{Kind: EvidenceKindCondition, Value: c.Builtin})
+	}
+	return result, true
+}
+
+// match gets the properties matching the name and value.
+func (m propertyMatcher) match(properties internal.PropertyValues) []internal.PropertyValue {
+	var result []internal.PropertyValue
+	for _, property := range getMatchedProperties(properties, m.Name) {
+		if m.matchesValue(strings.TrimSpace(property.Value)) {
+			result = append(result, property)
+		}
+	}
+	return result
+}
+
+func (m propertyMatcher) matchesValue(value string) bool {
+	if len(m.Values) == 0 && len(m.Prefixes) == 0 {
+		return true
+	}
+	for _, expected := range m.Values {
+		if strings.EqualFold(value, expected) {
+			return true
+		}
+	}
+	for _, prefix := range m.Prefixes {
+		if len(value) >= len(prefix) && strings.EqualFold(value[:len(prefix)], prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// extractDetectionFields gets the field name -> the distinct non-empty values, in the order of the properties.
+func extractDetectionFields(ctx detectionContext, fields []detectionField) map[string][]string {
+	result := make(map[string][]string)
+	for _, field := range fields {
+		if len(field.When) > 0 && !slices.ContainsFunc(field.When, func(condition detectionCondition) bool {
+			_, ok := condition.match(ctx)
+			return ok
+		}) {
+			continue
+		}
+		format := detectionFieldFormats[field.Format]
+		for _, pattern := range field.Properties {
+			for _, property := range getMatchedProperties(ctx.properties, pattern) {
+				if value := format(property.Value); value != "" {
+					result[field.Name] = internal.AppendAndDistinctInOrder(result[field.Name], value)
+				}
+			}
+		}
+	}
+	return result
+}
+
+func firstFieldValue(fields map[string][]string, name string) string {
+	if len(fields[name]) == 0 {
+		return ""
+	}
+	return fields[name][0]
+}
+
+// getMatchedProperties gets the property of the name under relaxed binding, or the properties matching the name
+// pattern having "*", ordered by the canonical property name.
+func getMatchedProperties(properties internal.PropertyValues, namePattern string) []internal.PropertyValue {
+	if !strings.Contains(namePattern, "*") {
+		if property, ok := properties.Get(namePattern); ok {
+			return []internal.PropertyValue{property}
+		}
+		return nil
+	}
+	var parts []string
+	for _, part := range strings.Split(internal.CanonicalPropertyName(namePattern), "*") {
+		parts = append(parts, regexp.QuoteMeta(part))
+	}
+	pattern := regexp.MustCompile("^" + strings.Join(parts, ".*") + "$")
+	var canonicalNames []string
+	for canonicalName := range properties {
+		if pattern.MatchString(canonicalName) {
+			canonicalNames = append(canonicalNames, canonicalName)
+		}
+	}
+	sort.Strings(canonicalNames)
+	var result []internal.PropertyValue
+	for _, canonicalName := range canonicalNames {
+		result = append(result, properties[canonicalName])
+	}
+	return result
+}
@@ -0,0 +1,230 @@
+package analyzer
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"ajpa/analyzer/internal"
+
+	"github.com/stretchr/testify/require"
+)
+
+func loadTestDetectionRules(t *testing.T) []detectionRule {
+	rules, err := loadDetectionRules("")
+	require.NoError(t, err)
+	return rules
+}
+
+func TestLoadDetectionRules(t *testing.T) {
+	var ids []string
+	for _, rule := range loadTestDetectionRules(t) {
+		ids = append(ids, rule.Id)
+	}
+	require.Equal(t, []string{"postgresql", "mysql", "sql-server", "mariadb", "redis", "mongo", "cosmos", "service-bus",
+		"event-hubs", "storage", "key-vault", "app-configuration"}, ids)
+}
+
+func TestLoadDetectionRulesWithUserRules(t *testing.T) {
+	userRulesPath := filepath.Join(t.TempDir(), "rules.yaml")
+	require.NoError(t, os.WriteFile(userRulesPath, []byte(`
+rules:
+  - id: redis
+    serviceType: redis
+    serviceName: cache
+    anyOf:
+      - dependencies: [com.contoso:contoso-cache-starter]
+  - id: cosmos
+    disabled: true
+  - id: contoso-messaging
+    serviceType: service-bus
+    anyOf:
+      - dependencies: [com.contoso:contoso-messaging-starter]
+    fields:
+      - name: queues
+        properties:
+          - "contoso.messaging.queues[*].name"
+`), 0600))
+	rules, err := loadDetectionRules(userRulesPath)
+	require.NoError(t, err)
+	var ids []string
+	for _, rule := range rules {
+		ids = append(ids, rule.Id)
+	}
+	require.Equal(t, []string{"postgresql", "mysql", "sql-server", "mariadb", "redis", "mongo", "service-bus",
+		"event-hubs", "storage", "key-vault", "app-configuration", "contoso-messaging"}, ids)
+
+	pom, err := internal.ParseTestPom(`<project><dependencies>
+		<dependency><groupId>com.contoso</groupId><artifactId>contoso-cache-starter</artifactId></dependency>
+		<dependency><groupId>com.contoso</groupId><artifactId>contoso-messaging-starter</artifactId></dependency>
+		</dependencies></project>`)
+	require.NoError(t, err)
+	result := ProjectAnalysisResult{}
+	require.NoError(t, addApplicationToResult(&result, "app", Application{}))
+	require.NoError(t, detectBackingServices(detectionContext{result: &result, applicationName: "app", pom: pom,
+		properties: internal.NewTestPropertyValues(map[string]string{
+			"contoso.messaging.queues[0].name": "orders",
+			"CONTOSO_MESSAGING_QUEUES_1_NAME":  "payments",
+		})}, rules))
+	require.Equal(t, map[string]Service{
+		"cache":                      AzureCacheForRedis{},
+		DefaultServiceBusServiceName: AzureServiceBus{Queues: []string{"orders", "payments"}},
+	}, result.Services)
+}
+
+func TestLoadDetectionRulesWithInvalidUserRules(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+	}{
+		{
+			name:    "unknown key",
+			content: "rules:\n  - id: redis\n    serviceType: redis\n    dependencies: [a:b]\n",
+		},
+		{
+			name:    "unknown service type",
+			content: "rules:\n  - id: kafka\n    serviceType: kafka\n    anyOf:\n      - dependencies: [a:b]\n",
+		},
+		{
+			name:    "no condition",
+			content: "rules:\n  - id: redis\n    serviceType: redis\n",
+		},
+		{
+			name:    "invalid coordinates",
+			content: "rules:\n  - id: redis\n    serviceType: redis\n    anyOf:\n      - dependencies: [redis]\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			userRulesPath := filepath.Join(t.TempDir(), "rules.yaml")
+			require.NoError(t, os.WriteFile(userRulesPath, []byte(tt.content), 0600))
+			_, err := loadDetectionRules(userRulesPath)
+			var configFileError *internal.ConfigFileError
+			require.ErrorAs(t, err, &configFileError)
+			require.Equal(t, userRulesPath, configFileError.FilePath)
+		})
+	}
+}
+
+func TestDetectBackingServices(t *testing.T) {
+	tests := []struct {
+		name        string
+		artifacts   []string
+		properties  map[string]string
+		annotations []internal.JavaAnnotation
+		expected    map[string]Service
+	}{
+		{
+			name:      "postgresql driver",
+			artifacts: []string{"org.postgresql:postgresql"},
+			expected:  map[string]Service{DefaultPostgresqlServiceName: AzureDatabaseForPostgresql{}},
+		},
+		{
+			name:       "postgresql driver with datasource url",
+			artifacts:  []string{"org.postgresql:postgresql"},
+			properties: map[string]string{"spring.datasource.url": "jdbc:h2:mem:petclinic"},
+		},
+		{
+			name:       "micronaut data dialect",
+			artifacts:  []string{"io.micronaut.data:micronaut-data-jdbc"},
+			properties: map[string]string{"datasources.default.dialect": "postgres"},
+			expected:   map[string]Service{DefaultPostgresqlServiceName: AzureDatabaseForPostgresql{}},
+		},
+		{
+			name:      "sql server driver",
+			artifacts: []string{"com.microsoft.sqlserver:mssql-jdbc"},
+			expected:  map[string]Service{DefaultSqlServerServiceName: AzureSqlDatabase{}},
+		},
+		{
+			name:       "micronaut data sql server dialect",
+			artifacts:  []string{"io.micronaut.data:micronaut-data-jdbc"},
+			properties: map[string]string{"datasources.default.dialect": "SQL_SERVER"},
+			expected:   map[string]Service{DefaultSqlServerServiceName: AzureSqlDatabase{}},
+		},
+		{
+			name:      "mariadb driver",
+			artifacts: []string{"org.mariadb.jdbc:mariadb-java-client"},
+			expected:  map[string]Service{DefaultMariadbServiceName: AzureDatabaseForMariadb{}},
+		},
+		{
+			name:       "micronaut dialect without micronaut data",
+			properties: map[string]string{"datasources.default.dialect": "POSTGRES"},
+		},
+		{
+			name:      "service bus listener of spring messaging",
+			artifacts: []string{"com.azure.spring:spring-messaging-azure-servicebus"},
+			annotations: []internal.JavaAnnotation{
+				{Name: "ServiceBusListener", Attributes: map[string][]string{"destination": {"refunds"}}},
+			},
+			expected: map[string]Service{
+				DefaultServiceBusServiceName: AzureServiceBus{Queues: []string{"refunds"}},
+			},
+		},
+		{
+			name:      "event hubs checkpoint store",
+			artifacts: []string{"com.azure.spring:spring-cloud-azure-stream-binder-eventhubs"},
+			properties: map[string]string{
+				"spring.cloud.stream.bindings.consume-in-0.destination":                        "telemetry",
+				"spring.cloud.stream.eventhubs.bindings.consume-in-0.consumer.checkpoint.mode": "MANUAL",
+				"spring.cloud.azure.eventhubs.processor.checkpoint-store.container-name":       "checkpoints",
+			},
+			expected: map[string]Service{
+				DefaultEventHubsServiceName: AzureEventHubs{Hubs: []string{"telemetry"}},
+				DefaultStorageServiceName:   AzureStorageAccount{Containers: []string{"checkpoints"}},
+			},
+		},
+		{
+			name:      "event hubs binder without input binding",
+			artifacts: []string{"com.azure.spring:spring-cloud-azure-stream-binder-eventhubs"},
+			properties: map[string]string{
+				"spring.cloud.stream.bindings.supply-out-0.destination":                  "telemetry",
+				"spring.cloud.azure.eventhubs.processor.checkpoint-store.container-name": "checkpoints",
+			},
+			expected: map[string]Service{
+				DefaultEventHubsServiceName: AzureEventHubs{Hubs: []string{"telemetry"}},
+				DefaultStorageServiceName:   AzureStorageAccount{},
+			},
+		},
+		{
+			name:      "key vault starter",
+			artifacts: []string{"com.azure.spring:spring-cloud-azure-starter-keyvault-secrets"},
+			properties: map[string]string{
+				"spring.cloud.azure.keyvault.secret.endpoint": "https://orders.vault.azure.net/",
+			},
+			expected: map[string]Service{
+				DefaultKeyVaultServiceName: AzureKeyVault{Endpoints: []string{"https://orders.vault.azure.net/"}},
+			},
+		},
+		{
+			name: "key vault property sources",
+			properties: map[string]string{
+				"spring.cloud.azure.keyvault.secret.property-sources[1].endpoint": "https://shared.vault.azure.net/",
+				"spring.cloud.azure.keyvault.secret.property-sources[0].endpoint": "https://orders.vault.azure.net/",
+			},
+			expected: map[string]Service{
+				DefaultKeyVaultServiceName: AzureKeyVault{
+					Endpoints: []string{"https://orders.vault.azure.net/", "https://shared.vault.azure.net/"},
+				},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pomContent := "<project><dependencies>"
+			for _, artifact := range tt.artifacts {
+				groupId, artifactId, _ := strings.Cut(artifact, ":")
+				pomContent += "<dependency><groupId>" + groupId + "</groupId><artifactId>" + artifactId +
+					"</artifactId></dependency>"
+			}
+			pom, err := internal.ParseTestPom(pomContent + "</dependencies></project>")
+			require.NoError(t, err)
+			result := ProjectAnalysisResult{}
+			require.NoError(t, addApplicationToResult(&result, "app", Application{}))
+			require.NoError(t, detectBackingServices(detectionContext{result: &result, applicationName: "app", pom: pom,
+				properties: internal.NewTestPropertyValues(tt.properties), annotations: tt.annotations},
+				loadTestDetectionRules(t)))
+			require.Equal(t, tt.expected, result.Services)
+		})
+	}
+}
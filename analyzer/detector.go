@@ -0,0 +1,236 @@
+package analyzer
+
+import (
+	"fmt"
+	"path/filepath"
+	"slices"
+	"sync"
+
+	"ajpa/analyzer/internal"
+)
+
+// Detector detects the backing services used by an application module, like the services wrapped by the internal
+// starters of an organization. Detectors registered by RegisterDetector run after the built-in detection rules, see
+// detection_rules.yaml.
+type Detector interface {
+	// Name identifies the detector in errors.
+	Name() string
+	// Detect returns the backing services used by the module. Services having the same name as a service detected
+	// before are not added again, only their evidence is added.
+	Detect(module Module) ([]DetectedService, error)
+}
+
+// DetectedService is a backing service returned by Detector. Service should be one of the service types in this
+// package, like AzureServiceBus, so that it can be converted to azd resources.
+type DetectedService struct {
+	Name     string
+	Service  Service
+	Evidence []Evidence
+}
+
+// Module is an application module passed to Detector.
+type Module struct {
+	ApplicationName string
+	Framework       Framework
+	// ProjectPath is the absolute path of the directory having the build file.
+	ProjectPath string
+	// BuildFilePath is relative to the project root path, like "orders/pom.xml" or "orders/build.gradle".
+	BuildFilePath string
+	// Dependencies are from the effective pom or the Gradle build file. Gradle plugins are also in Plugins, by their
+	// plugin marker coordinates like "io.quarkus:io.quarkus.gradle.plugin".
+	Dependencies []Artifact
+	Plugins      []Artifact
+	// Properties are the resolved properties, same as Application.Properties.
+	Properties map[string]PropertyValue
+	// Annotations are the annotations in the Java and Kotlin source files.
+	Annotations []Annotation
+}
+
+// Artifact is a dependency or plugin of the build model.
+type Artifact struct {
+	GroupId    string
+	ArtifactId string
+	// Version and Scope are empty if not declared.
+	Version string
+	Scope   string
+}
+
+// Annotation is an annotation in the Java and Kotlin source files, like `@KafkaListener(topics = "orders")`.
+type Annotation struct {
+	// Name is the simple name, like "KafkaListener".
+	Name string
+	// Attributes maps attribute names to their string literal values. The attribute of a single unnamed value is
+	// "value".
+	Attributes map[string][]string
+	// FilePath is relative to the project root path. Line starts from 1.
+	FilePath string
+	Line     int
+}
+
+// HasDependency checks whether the module depends on the artifact.
+func (m Module) HasDependency(groupId string, artifactId string) bool {
+	return slices.ContainsFunc(m.Dependencies, func(artifact Artifact) bool {
+		return artifact.GroupId == groupId && artifact.ArtifactId == artifactId
+	})
+}
+
+// GetProperty gets the value of the property whose name matches the given name under relaxed binding, like
+// "spring.datasource.url" matching "SPRING_DATASOURCE_URL".
+func (m Module) GetProperty(name string) (string, bool) {
+	value, ok := m.Properties[internal.CanonicalPropertyName(name)]
+	return value.Value, ok
+}
+
+// GetAnnotations gets the annotations having the simple name.
+func (m Module) GetAnnotations(name string) []Annotation {
+	var result []Annotation
+	for _, annotation := range m.Annotations {
+		if annotation.Name == name {
+			result = append(result, annotation)
+		}
+	}
+	return result
+}
+
+// DependencyEvidence is the evidence of a dependency in the module's build file.
+func (m Module) DependencyEvidence(groupId string, artifactId string) Evidence {
+	return Evidence{Kind: EvidenceKindDependency, Value: groupId + ":" + artifactId, FilePath: m.BuildFilePath}
+}
+
+// PropertyEvidence is the evidence of a property, located at where the property is defined.
+func (m Module) PropertyEvidence(name string) Evidence {
+	return newPropertyEvidence(m.Properties, name)
+}
+
+// AnnotationEvidence is the evidence of an annotation, located at where the annotation is used.
+func (m Module) AnnotationEvidence(annotation Annotation) Evidence {
+	return newAnnotationEvidence(annotation)
+}
+
+func newPropertyEvidence(properties map[string]PropertyValue, name string) Evidence {
+	if value, ok := properties[internal.CanonicalPropertyName(name)]; ok {
+		return Evidence{Kind: EvidenceKindProperty, Value: value.Name, FilePath: value.Origin.FilePath,
+			Line: value.Origin.Line}
+	}
+	return Evidence{Kind: EvidenceKindProperty, Value: name}
+}
+
+func newAnnotationEvidence(annotation Annotation) Evidence {
+	return Evidence{Kind: EvidenceKindAnnotation, Value: annotation.Name, FilePath: annotation.FilePath,
+		Line: annotation.Line}
+}
+
+var (
+	detectorsMutex sync.RWMutex
+	detectors      []Detector
+)
+
+// RegisterDetector registers the detector, which is used by all following analyses. It's usually called in the init
+// function of the package providing the detector.
+func RegisterDetector(detector Detector) {
+	detectorsMutex.Lock()
+	defer detectorsMutex.Unlock()
+	detectors = append(detectors, detector)
+}
+
+func getRegisteredDetectors() []Detector {
+	detectorsMutex.RLock()
+	defer detectorsMutex.RUnlock()
+	return slices.Clone(detectors)
+}
+
+// detectByRegisteredDetectors runs the registered detectors on the module of the application.
+func detectByRegisteredDetectors(ctx detectionContext) error {
+	registeredDetectors := getRegisteredDetectors()
+	if len(registeredDetectors) == 0 {
+		return nil
+	}
+	module := newModule(ctx)
+	for _, detector := range registeredDetectors {
+		services, err := detector.Detect(module)
+		if err != nil {
+			return fmt.Errorf("detecting services of %s by %s: %w", ctx.applicationName, detector.Name(), err)
+		}
+		for _, service := range services {
+			if err = addDetectedServiceToResult(ctx.result, ctx.applicationName, service); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func newModule(ctx detectionContext) Module {
+	application := ctx.result.Applications[ctx.applicationName]
+	module := Module{
+		ApplicationName: ctx.applicationName,
+		Framework:       application.Framework,
+		ProjectPath:     ctx.projectPath,
+		BuildFilePath:   ctx.pom.PomFilePath,
+		Properties:      application.Properties,
+	}
+	for _, dependency := range ctx.pom.Dependencies {
+		module.Dependencies = append(module.Dependencies, Artifact{
+			GroupId:    dependency.GroupId,
+			ArtifactId: dependency.ArtifactId,
+			Version:    dependency.Version,
+			Scope:      dependency.Scope,
+		})
+	}
+	for _, plugin := range ctx.pom.Build.Plugins {
+		module.Plugins = append(module.Plugins, Artifact{
+			GroupId:    plugin.GroupId,
+			ArtifactId: plugin.ArtifactId,
+			Version:    plugin.Version,
+		})
+	}
+	for _, annotation := range ctx.annotations {
+		module.Annotations = append(module.Annotations, newAnnotation(ctx.projectRootPath, annotation))
+	}
+	return module
+}
+
+func newAnnotation(projectRootPath string, annotation internal.JavaAnnotation) Annotation {
+	filePath := annotation.FilePath
+	if relativePath, err := filepath.Rel(projectRootPath, filePath); err == nil && filePath != "" {
+		filePath = relativePath
+	}
+	return Annotation{
+		Name:       annotation.Name,
+		Attributes: annotation.Attributes,
+		FilePath:   filePath,
+		Line:       annotation.Line,
+	}
+}
+
+// addDetectedServiceToResult adds the backing service of the application, and its evidence. If the application
+// already uses a service of the same name, the entities of the detected service are merged into it, see
+// mergeServices.
+func addDetectedServiceToResult(result *ProjectAnalysisResult, applicationName string,
+	detectedService DetectedService) error {
+	if _, ok := result.ApplicationToBackingService[applicationName][detectedService.Name]; ok {
+		result.Services[detectedService.Name] = mergeServices(result.Services[detectedService.Name],
+			detectedService.Service)
+	} else {
+		err := addApplicationRelatedBackingServiceToResult(result, applicationName, detectedService.Name,
+			detectedService.Service)
+		if err != nil {
+			return err
+		}
+	}
+	if len(detectedService.Evidence) == 0 {
+		return nil
+	}
+	application := result.Applications[applicationName]
+	if application.ServiceEvidence == nil {
+		application.ServiceEvidence = make(map[string][]Evidence)
+	}
+	for _, evidence := range detectedService.Evidence {
+		if !slices.Contains(application.ServiceEvidence[detectedService.Name], evidence) {
+			application.ServiceEvidence[detectedService.Name] = append(
+				application.ServiceEvidence[detectedService.Name], evidence)
+		}
+	}
+	result.Applications[applicationName] = application
+	return nil
+}
@@ -0,0 +1,135 @@
+package analyzer
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"ajpa/analyzer/internal"
+
+	"github.com/stretchr/testify/require"
+)
+
+// contosoMessagingDetector detects the Service Bus queues used by an internal starter.
+type contosoMessagingDetector struct {
+	err error
+}
+
+func (d contosoMessagingDetector) Name() string {
+	return "contoso-messaging"
+}
+
+func (d contosoMessagingDetector) Detect(module Module) ([]DetectedService, error) {
+	if d.err != nil {
+		return nil, d.err
+	}
+	if !module.HasDependency("com.contoso", "contoso-messaging-starter") {
+		return nil, nil
+	}
+	queue, _ := module.GetProperty("contoso.messaging.queue")
+	return []DetectedService{{
+		Name:    DefaultServiceBusServiceName,
+		Service: AzureServiceBus{Queues: []string{queue}},
+		Evidence: []Evidence{
+			module.DependencyEvidence("com.contoso", "contoso-messaging-starter"),
+			module.PropertyEvidence("contoso.messaging.queue"),
+		},
+	}}, nil
+}
+
+func registerTestDetector(t *testing.T, detector Detector) {
+	registered := getRegisteredDetectors()
+	t.Cleanup(func() {
+		detectorsMutex.Lock()
+		defer detectorsMutex.Unlock()
+		detectors = registered
+	})
+	RegisterDetector(detector)
+}
+
+func newTestDetectionContext(t *testing.T, result *ProjectAnalysisResult, artifactIds ...string) detectionContext {
+	pomContent := "<project><dependencies>"
+	for _, artifactId := range artifactIds {
+		pomContent += "<dependency><groupId>com.contoso</groupId><artifactId>" + artifactId + "</artifactId>" +
+			"</dependency>"
+	}
+	pom, err := internal.ParseTestPom(pomContent + "</dependencies></project>")
+	require.NoError(t, err)
+	pom.PomFilePath = filepath.Join("orders", "pom.xml")
+	require.NoError(t, addApplicationToResult(result, "orders", Application{
+		Properties: map[string]PropertyValue{
+			"contoso.messaging.queue": {Name: "CONTOSO_MESSAGING_QUEUE", Value: "orders",
+				Origin: PropertyOrigin{FilePath: ".env", Line: 1}},
+		},
+	}))
+	return detectionContext{
+		result:          result,
+		applicationName: "orders",
+		projectRootPath: "/workspace",
+		projectPath:     filepath.Join("/workspace", "orders"),
+		pom:             pom,
+		properties:      internal.NewTestPropertyValues(map[string]string{"CONTOSO_MESSAGING_QUEUE": "orders"}),
+	}
+}
+
+func TestDetectByRegisteredDetectors(t *testing.T) {
+	registerTestDetector(t, contosoMessagingDetector{})
+	result := ProjectAnalysisResult{}
+	ctx := newTestDetectionContext(t, &result, "contoso-messaging-starter")
+	require.NoError(t, detectByRegisteredDetectors(ctx))
+	require.Equal(t, AzureServiceBus{Queues: []string{"orders"}}, result.Services[DefaultServiceBusServiceName])
+	require.Equal(t, map[string][]Evidence{
+		DefaultServiceBusServiceName: {
+			{Kind: EvidenceKindDependency, Value: "com.contoso:contoso-messaging-starter",
+				FilePath: filepath.Join("orders", "pom.xml")},
+			{Kind: EvidenceKindProperty, Value: "CONTOSO_MESSAGING_QUEUE", FilePath: ".env", Line: 1},
+		},
+	}, result.Applications["orders"].ServiceEvidence)
+}
+
+func TestDetectByRegisteredDetectorsMergesDetectedService(t *testing.T) {
+	registerTestDetector(t, contosoMessagingDetector{})
+	result := ProjectAnalysisResult{}
+	ctx := newTestDetectionContext(t, &result, "contoso-messaging-starter")
+	require.NoError(t, addDetectedServiceToResult(&result, "orders", DetectedService{
+		Name:     DefaultServiceBusServiceName,
+		Service:  AzureServiceBus{Queues: []string{"payments"}},
+		Evidence: []Evidence{{Kind: EvidenceKindAnnotation, Value: "JmsListener"}},
+	}))
+	require.NoError(t, detectByRegisteredDetectors(ctx))
+	require.Equal(t, AzureServiceBus{Queues: []string{"payments", "orders"}},
+		result.Services[DefaultServiceBusServiceName])
+	require.Len(t, result.Applications["orders"].ServiceEvidence[DefaultServiceBusServiceName], 3)
+}
+
+func TestDetectByRegisteredDetectorsWithError(t *testing.T) {
+	registerTestDetector(t, contosoMessagingDetector{err: errors.New("boom")})
+	result := ProjectAnalysisResult{}
+	err := detectByRegisteredDetectors(newTestDetectionContext(t, &result))
+	require.ErrorContains(t, err, "detecting services of orders by contoso-messaging: boom")
+}
+
+func TestNewModule(t *testing.T) {
+	result := ProjectAnalysisResult{}
+	ctx := newTestDetectionContext(t, &result, "contoso-messaging-starter")
+	ctx.annotations = []internal.JavaAnnotation{{
+		Name:       "JmsListener",
+		Attributes: map[string][]string{"destination": {"orders"}},
+		FilePath:   filepath.Join("/workspace", "orders", "src", "main", "java", "Listener.java"),
+		Line:       12,
+	}}
+	module := newModule(ctx)
+	require.Equal(t, "orders", module.ApplicationName)
+	require.Equal(t, filepath.Join("orders", "pom.xml"), module.BuildFilePath)
+	require.Equal(t, []Artifact{{GroupId: "com.contoso", ArtifactId: "contoso-messaging-starter"}},
+		module.Dependencies)
+	value, ok := module.GetProperty("contoso.messaging.queue")
+	require.True(t, ok)
+	require.Equal(t, "orders", value)
+	require.Equal(t, []Annotation{{
+		Name:       "JmsListener",
+		Attributes: map[string][]string{"destination": {"orders"}},
+		FilePath:   filepath.Join("orders", "src", "main", "java", "Listener.java"),
+		Line:       12,
+	}}, module.GetAnnotations("JmsListener"))
+}
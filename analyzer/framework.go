@@ -0,0 +1,208 @@
+package analyzer
+
+import (
+	"errors"
+	"strings"
+
+	"ajpa/analyzer/internal"
+)
+
+// detectFramework detects the framework of runnable applications by the plugin packaging them:
+//   - Azure Functions: azure-functions-maven-plugin or the com.microsoft.azure.azurefunctions Gradle plugin, or the
+//     azure-functions-java-library dependency. It takes precedence, because Spring Cloud Function applications
+//     running on Azure Functions may also have the Spring Boot plugin.
+//   - Spring Boot: spring-boot-maven-plugin or the org.springframework.boot Gradle plugin.
+//   - Quarkus: quarkus-maven-plugin or the io.quarkus Gradle plugin.
+//   - Micronaut: micronaut-maven-plugin or the io.micronaut.application Gradle plugin.
+//
+// False is returned if the project is not a runnable application, like libraries and parent poms.
+func detectFramework(pom internal.Pom) (Framework, bool) {
+	if len(pom.Modules) > 0 {
+		return "", false
+	}
+	switch {
+	case hasPlugin(pom, "com.microsoft.azure", "azure-functions-maven-plugin"),
+		hasGradlePlugin(pom, "com.microsoft.azure.azurefunctions"),
+		hasDependency(pom, "com.microsoft.azure.functions", "azure-functions-java-library"):
+		return FrameworkAzureFunctions, true
+	case hasPlugin(pom, "org.springframework.boot", "spring-boot-maven-plugin"),
+		hasGradlePlugin(pom, "org.springframework.boot"):
+		return FrameworkSpringBoot, true
+	case hasPlugin(pom, "io.quarkus", "quarkus-maven-plugin"),
+		hasPlugin(pom, "io.quarkus.platform", "quarkus-maven-plugin"),
+		hasGradlePlugin(pom, "io.quarkus"):
+		return FrameworkQuarkus, true
+	case hasPlugin(pom, "io.micronaut.maven", "micronaut-maven-plugin"),
+		hasPlugin(pom, "io.micronaut.build", "micronaut-maven-plugin"),
+		hasGradlePlugin(pom, "io.micronaut.application"),
+		hasGradlePlugin(pom, "io.micronaut.minimal.application"):
+		return FrameworkMicronaut, true
+	default:
+		return "", false
+	}
+}
+
+func hasPlugin(pom internal.Pom, groupId string, artifactId string) bool {
+	for _, plugin := range pom.Build.Plugins {
+		if plugin.GroupId == groupId && plugin.ArtifactId == artifactId {
+			return true
+		}
+	}
+	return false
+}
+
+// hasGradlePlugin checks whether the Gradle plugin is applied, by its plugin marker coordinates.
+func hasGradlePlugin(pom internal.Pom, id string) bool {
+	return hasPlugin(pom, id, id+".gradle.plugin")
+}
+
+// readApplicationConfig reads the config of the application. Quarkus and Micronaut read application.properties and
+// application.yml like Spring Boot, but activate profiles differently:
+//   - Quarkus uses the profile in QUARKUS_PROFILE, which defaults to "prod". Properties prefixed by the profile like
+//     "%prod.quarkus.http.port" take precedence.
+//   - Micronaut uses the environments in MICRONAUT_ENVIRONMENTS, which activate files like application-{env}.yml.
+//   - Azure Functions use the app settings, which are read from local.settings.json. Same as environment variables,
+//     they take precedence over the config files.
+func readApplicationConfig(projectPath string, framework Framework,
+	options internal.SpringBootConfigOptions) (internal.SpringBootConfig, error) {
+	switch framework {
+	case FrameworkQuarkus:
+		profile := internal.GetQuarkusProfile()
+		options.Profiles = []string{profile}
+		config, err := internal.ReadSpringBootConfig(projectPath, options)
+		if err != nil {
+			return internal.SpringBootConfig{}, err
+		}
+		config.PropertyValues = internal.ApplyQuarkusProfile(config.PropertyValues, profile)
+		return config, nil
+	case FrameworkMicronaut:
+		options.Profiles = internal.GetMicronautEnvironments()
+	case FrameworkAzureFunctions:
+		config, err := internal.ReadSpringBootConfig(projectPath, options)
+		if err != nil {
+			return internal.SpringBootConfig{}, err
+		}
+		settings, err := internal.ReadFunctionsLocalSettings(projectPath)
+		var configFileError *internal.ConfigFileError
+		if options.SkipMalformedFiles && errors.As(err, &configFileError) {
+			config.SkippedFiles = append(config.SkippedFiles, configFileError)
+		} else if err != nil {
+			return internal.SpringBootConfig{}, err
+		}
+		if config.PropertyValues == nil {
+			config.PropertyValues = make(internal.PropertyValues)
+		}
+		config.PropertyValues.Merge(settings)
+		return config, nil
+	}
+	return internal.ReadSpringBootConfig(projectPath, options)
+}
+
+// detectFrameworkWebApplicationType detects whether the application serves HTTP. Quarkus serves HTTP if any HTTP
+// extension exists, and Micronaut serves HTTP if an HTTP server exists. Both use Netty or Vert.x which are reactive,
+// except the servlet based ones like quarkus-undertow and micronaut-http-server-tomcat. Azure Functions never serve
+// HTTP by themselves.
+func detectFrameworkWebApplicationType(framework Framework, pom internal.Pom, properties internal.PropertyValues,
+	annotations []internal.JavaAnnotation) WebApplicationType {
+	switch framework {
+	case FrameworkQuarkus:
+		switch {
+		case hasDependency(pom, "io.quarkus", "quarkus-undertow"):
+			return WebApplicationTypeServlet
+		case hasQuarkusRestServerExtension(pom),
+			hasDependency(pom, "io.quarkus", "quarkus-vertx-http"),
+			hasDependency(pom, "io.quarkus", "quarkus-reactive-routes"),
+			hasDependency(pom, "io.quarkus", "quarkus-smallrye-graphql"),
+			hasDependencyWithPrefix(pom, "io.quarkus", "quarkus-websockets"):
+			return WebApplicationTypeReactive
+		default:
+			return WebApplicationTypeNone
+		}
+	case FrameworkAzureFunctions:
+		// HTTP triggers are served by the Functions host, not by the application.
+		return WebApplicationTypeNone
+	case FrameworkMicronaut:
+		switch {
+		case hasDependencyWithPrefix(pom, "io.micronaut.servlet", "micronaut-http-server-"):
+			return WebApplicationTypeServlet
+		case hasDependency(pom, "io.micronaut", "micronaut-http-server-netty"):
+			return WebApplicationTypeReactive
+		default:
+			return WebApplicationTypeNone
+		}
+	default:
+		return detectWebApplicationType(pom, properties, annotations)
+	}
+}
+
+// getFrameworkServerPort gets the HTTP port from server.port, quarkus.http.port or micronaut.server.port, which all
+// default to 8080.
+func getFrameworkServerPort(framework Framework, properties internal.PropertyValues) int {
+	switch framework {
+	case FrameworkQuarkus:
+		return internal.GetPort(properties, "quarkus.http.port", internal.DefaultServerPort)
+	case FrameworkMicronaut:
+		return internal.GetPort(properties, "micronaut.server.port", internal.DefaultServerPort)
+	default:
+		return internal.GetServerPort(properties)
+	}
+}
+
+// detectFrameworkHealthProbes detects the health endpoints served by SmallRye Health of Quarkus or by Micronaut
+// management. Same as detectHealthProbes, the liveness endpoint is used by the startup probe if there is no startup
+// endpoint.
+func detectFrameworkHealthProbes(framework Framework, pom internal.Pom, properties internal.PropertyValues,
+	webApplicationType WebApplicationType, port int) *HealthProbes {
+	var endpoints internal.HealthProbeEndpoints
+	var ok bool
+	switch framework {
+	case FrameworkQuarkus:
+		if webApplicationType == WebApplicationTypeNone ||
+			!hasDependency(pom, "io.quarkus", "quarkus-smallrye-health") {
+			return nil
+		}
+		endpoints, ok = internal.GetQuarkusHealthProbeEndpoints(properties, port)
+	case FrameworkMicronaut:
+		if webApplicationType == WebApplicationTypeNone ||
+			!hasDependency(pom, "io.micronaut", "micronaut-management") {
+			return nil
+		}
+		endpoints, ok = internal.GetMicronautHealthProbeEndpoints(properties, port)
+	default:
+		return detectHealthProbes(pom, properties, webApplicationType, port)
+	}
+	if !ok {
+		return nil
+	}
+	startupPath := endpoints.StartupPath
+	if startupPath == "" {
+		startupPath = endpoints.LivenessPath
+	}
+	return &HealthProbes{
+		Port:          endpoints.Port,
+		LivenessPath:  endpoints.LivenessPath,
+		ReadinessPath: endpoints.ReadinessPath,
+		StartupPath:   startupPath,
+	}
+}
+
+// hasQuarkusRestServerExtension checks REST extensions like quarkus-rest and quarkus-resteasy-reactive-jackson. REST
+// clients like quarkus-rest-client don't serve HTTP.
+func hasQuarkusRestServerExtension(pom internal.Pom) bool {
+	for _, dep := range pom.Dependencies {
+		if dep.GroupId == "io.quarkus" && strings.HasPrefix(dep.ArtifactId, "quarkus-rest") &&
+			!strings.Contains(dep.ArtifactId, "-client") {
+			return true
+		}
+	}
+	return false
+}
+
+func hasDependencyWithPrefix(pom internal.Pom, groupId string, artifactIdPrefix string) bool {
+	for _, dep := range pom.Dependencies {
+		if dep.GroupId == groupId && strings.HasPrefix(dep.ArtifactId, artifactIdPrefix) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,169 @@
+package analyzer
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"ajpa/analyzer/internal"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDetectFramework(t *testing.T) {
+	tests := []struct {
+		name       string
+		pom        string
+		expected   Framework
+		expectedOk bool
+	}{
+		{
+			name: "spring boot",
+			pom: `<project><build><plugins><plugin>
+				<groupId>org.springframework.boot</groupId><artifactId>spring-boot-maven-plugin</artifactId>
+				</plugin></plugins></build></project>`,
+			expected:   FrameworkSpringBoot,
+			expectedOk: true,
+		},
+		{
+			name: "quarkus",
+			pom: `<project><build><plugins><plugin>
+				<groupId>io.quarkus.platform</groupId><artifactId>quarkus-maven-plugin</artifactId>
+				</plugin></plugins></build></project>`,
+			expected:   FrameworkQuarkus,
+			expectedOk: true,
+		},
+		{
+			name: "micronaut",
+			pom: `<project><build><plugins><plugin>
+				<groupId>io.micronaut.maven</groupId><artifactId>micronaut-maven-plugin</artifactId>
+				</plugin></plugins></build></project>`,
+			expected:   FrameworkMicronaut,
+			expectedOk: true,
+		},
+		{
+			name: "azure functions",
+			pom: `<project>
+				<dependencies><dependency>
+				<groupId>com.microsoft.azure.functions</groupId><artifactId>azure-functions-java-library</artifactId>
+				</dependency></dependencies>
+				<build><plugins><plugin>
+				<groupId>org.springframework.boot</groupId><artifactId>spring-boot-maven-plugin</artifactId>
+				</plugin></plugins></build>
+				</project>`,
+			expected:   FrameworkAzureFunctions,
+			expectedOk: true,
+		},
+		{
+			name: "parent pom",
+			pom: `<project><modules><module>application</module></modules><build><plugins><plugin>
+				<groupId>io.quarkus</groupId><artifactId>quarkus-maven-plugin</artifactId>
+				</plugin></plugins></build></project>`,
+			expectedOk: false,
+		},
+		{
+			name: "library",
+			pom: `<project><dependencies><dependency>
+				<groupId>io.quarkus</groupId><artifactId>quarkus-core</artifactId>
+				</dependency></dependencies></project>`,
+			expectedOk: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pom, err := internal.ParseTestPom(tt.pom)
+			require.NoError(t, err)
+			framework, ok := detectFramework(pom)
+			require.Equal(t, tt.expectedOk, ok)
+			require.Equal(t, tt.expected, framework)
+		})
+	}
+}
+
+func TestDetectFrameworkWebApplicationType(t *testing.T) {
+	tests := []struct {
+		name      string
+		framework Framework
+		pom       string
+		expected  WebApplicationType
+	}{
+		{
+			name:      "quarkus rest",
+			framework: FrameworkQuarkus,
+			pom: `<project><dependencies><dependency>
+				<groupId>io.quarkus</groupId><artifactId>quarkus-resteasy-reactive-jackson</artifactId>
+				</dependency></dependencies></project>`,
+			expected: WebApplicationTypeReactive,
+		},
+		{
+			name:      "quarkus rest client",
+			framework: FrameworkQuarkus,
+			pom: `<project><dependencies><dependency>
+				<groupId>io.quarkus</groupId><artifactId>quarkus-rest-client-jackson</artifactId>
+				</dependency></dependencies></project>`,
+			expected: WebApplicationTypeNone,
+		},
+		{
+			name:      "quarkus undertow",
+			framework: FrameworkQuarkus,
+			pom: `<project><dependencies><dependency>
+				<groupId>io.quarkus</groupId><artifactId>quarkus-undertow</artifactId>
+				</dependency></dependencies></project>`,
+			expected: WebApplicationTypeServlet,
+		},
+		{
+			name:      "micronaut netty",
+			framework: FrameworkMicronaut,
+			pom: `<project><dependencies><dependency>
+				<groupId>io.micronaut</groupId><artifactId>micronaut-http-server-netty</artifactId>
+				</dependency></dependencies></project>`,
+			expected: WebApplicationTypeReactive,
+		},
+		{
+			name:      "micronaut tomcat",
+			framework: FrameworkMicronaut,
+			pom: `<project><dependencies><dependency>
+				<groupId>io.micronaut.servlet</groupId><artifactId>micronaut-http-server-tomcat</artifactId>
+				</dependency></dependencies></project>`,
+			expected: WebApplicationTypeServlet,
+		},
+		{
+			name:      "micronaut without http server",
+			framework: FrameworkMicronaut,
+			pom:       `<project></project>`,
+			expected:  WebApplicationTypeNone,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pom, err := internal.ParseTestPom(tt.pom)
+			require.NoError(t, err)
+			require.Equal(t, tt.expected, detectFrameworkWebApplicationType(tt.framework, pom, nil, nil))
+		})
+	}
+}
+
+func TestReadApplicationConfigOfAzureFunctions(t *testing.T) {
+	projectPath := t.TempDir()
+	resourcesPath := filepath.Join(projectPath, "src", "main", "resources")
+	require.NoError(t, os.MkdirAll(resourcesPath, 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(resourcesPath, "application.properties"),
+		[]byte("OrdersQueue=orders-dev\nspring.main.banner-mode=off\n"), 0600))
+	require.NoError(t, os.WriteFile(filepath.Join(projectPath, "local.settings.json"),
+		[]byte(`{"Values": {"OrdersQueue": "orders"}}`), 0600))
+	config, err := readApplicationConfig(projectPath, FrameworkAzureFunctions, internal.SpringBootConfigOptions{})
+	require.NoError(t, err)
+	require.Equal(t, map[string]string{
+		"OrdersQueue":             "orders",
+		"spring.main.banner-mode": "off",
+	}, config.PropertyValues.ToMap())
+
+	require.NoError(t, os.WriteFile(filepath.Join(projectPath, "local.settings.json"), []byte(`{`), 0600))
+	_, err = readApplicationConfig(projectPath, FrameworkAzureFunctions, internal.SpringBootConfigOptions{})
+	require.Error(t, err)
+	config, err = readApplicationConfig(projectPath, FrameworkAzureFunctions,
+		internal.SpringBootConfigOptions{SkipMalformedFiles: true})
+	require.NoError(t, err)
+	require.Len(t, config.SkippedFiles, 1)
+	require.Equal(t, "orders-dev", config.PropertyValues.ToMap()["OrdersQueue"])
+}
@@ -0,0 +1,131 @@
+package internal
+
+import (
+	"path"
+	"strconv"
+	"strings"
+)
+
+// HealthProbeEndpoints are the HTTP health endpoints used by container probes.
+type HealthProbeEndpoints struct {
+	Port          int
+	LivenessPath  string
+	ReadinessPath string
+	// StartupPath is empty if there is no dedicated startup endpoint, like Spring Boot Actuator.
+	StartupPath string
+}
+
+// GetHealthProbeEndpoints gets the health endpoints served by Spring Boot Actuator. serverPort is the application's
+// HTTP port, and reactive means the application is a WebFlux application.
+//
+// The endpoints are served on management.server.port if it's set, otherwise on the server port with the server's
+// context path. The health endpoint is {base-path}/health, where base-path is management.endpoints.web.base-path and
+// defaults to "/actuator". If management.endpoint.health.probes.enabled is true, or the liveness and readiness health
+// groups are configured, {base-path}/health/liveness and {base-path}/health/readiness are used. Otherwise, the health
+// endpoint is used for both.
+//
+// False is returned if the health endpoint is not available over HTTP.
+func GetHealthProbeEndpoints(properties PropertyValues, serverPort int,
+	reactive bool) (HealthProbeEndpoints, bool) {
+	if !isHealthEndpointExposed(properties) {
+		return HealthProbeEndpoints{}, false
+	}
+	port := serverPort
+	var contextPath string
+	if value, ok := GetPropertyValue(properties, "management.server.port"); ok && strings.TrimSpace(value) != "" {
+		managementPort, err := strconv.Atoi(strings.TrimSpace(value))
+		if err != nil || managementPort < 0 {
+			// -1 disables the HTTP endpoints.
+			return HealthProbeEndpoints{}, false
+		}
+		if managementPort > 0 && managementPort != serverPort {
+			port = managementPort
+			contextPath = GetFirstPropertyValue(properties, "management.server.base-path",
+				"management.server.servlet.context-path")
+		}
+	}
+	if port == serverPort {
+		if reactive {
+			contextPath = GetFirstPropertyValue(properties, "spring.webflux.base-path")
+		} else {
+			contextPath = GetFirstPropertyValue(properties, "server.servlet.context-path")
+		}
+	}
+	basePath := "/actuator"
+	if value, ok := GetPropertyValue(properties, "management.endpoints.web.base-path"); ok {
+		basePath = strings.TrimSpace(value)
+	}
+	healthPath := "health"
+	if value, ok := GetPropertyValue(properties, "management.endpoints.web.path-mapping.health"); ok &&
+		strings.TrimSpace(value) != "" {
+		healthPath = strings.TrimSpace(value)
+	}
+	healthPath = path.Join("/", contextPath, basePath, healthPath)
+	result := HealthProbeEndpoints{Port: port, LivenessPath: healthPath, ReadinessPath: healthPath}
+	probesEnabled := isTrue(properties, "management.endpoint.health.probes.enabled")
+	if probesEnabled || hasPropertyWithPrefix(properties, "management.endpoint.health.group.liveness.") {
+		result.LivenessPath = path.Join(healthPath, "liveness")
+	}
+	if probesEnabled || hasPropertyWithPrefix(properties, "management.endpoint.health.group.readiness.") {
+		result.ReadinessPath = path.Join(healthPath, "readiness")
+	}
+	return result, true
+}
+
+// isHealthEndpointExposed checks whether the health endpoint is enabled and exposed over HTTP. The health endpoint is
+// exposed by default.
+func isHealthEndpointExposed(properties PropertyValues) bool {
+	if value, ok := GetPropertyValue(properties, "management.endpoint.health.enabled"); ok {
+		if strings.EqualFold(strings.TrimSpace(value), "false") {
+			return false
+		}
+	} else if isFalse(properties, "management.endpoints.enabled-by-default") {
+		return false
+	}
+	if include := GetPropertyValueList(properties, "management.endpoints.web.exposure.include"); len(include) > 0 &&
+		!containsEndpointId(include, "health") {
+		return false
+	}
+	return !containsEndpointId(GetPropertyValueList(properties, "management.endpoints.web.exposure.exclude"), "health")
+}
+
+func containsEndpointId(endpointIds []string, endpointId string) bool {
+	for _, id := range endpointIds {
+		if id = strings.TrimSpace(id); id == "*" || id == endpointId {
+			return true
+		}
+	}
+	return false
+}
+
+// GetFirstPropertyValue gets the trimmed value of the first property which is set and not blank. Empty is returned if
+// none is set.
+func GetFirstPropertyValue(properties PropertyValues, names ...string) string {
+	for _, name := range names {
+		if value, ok := GetPropertyValue(properties, name); ok && strings.TrimSpace(value) != "" {
+			return strings.TrimSpace(value)
+		}
+	}
+	return ""
+}
+
+func isTrue(properties PropertyValues, name string) bool {
+	value, ok := GetPropertyValue(properties, name)
+	return ok && strings.EqualFold(strings.TrimSpace(value), "true")
+}
+
+func isFalse(properties PropertyValues, name string) bool {
+	value, ok := GetPropertyValue(properties, name)
+	return ok && strings.EqualFold(strings.TrimSpace(value), "false")
+}
+
+// hasPropertyWithPrefix checks whether any property name starts with the prefix under relaxed binding.
+func hasPropertyWithPrefix(properties PropertyValues, prefix string) bool {
+	canonicalPrefix := CanonicalPropertyName(prefix)
+	for canonicalName := range properties {
+		if strings.HasPrefix(canonicalName, canonicalPrefix) {
+			return true
+		}
+	}
+	return false
+}
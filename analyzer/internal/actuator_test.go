@@ -0,0 +1,87 @@
+package internal
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetHealthProbeEndpoints(t *testing.T) {
+	tests := []struct {
+		name       string
+		properties map[string]string
+		reactive   bool
+		expected   HealthProbeEndpoints
+		expectedOk bool
+	}{
+		{
+			name:       "default",
+			properties: map[string]string{},
+			expected:   HealthProbeEndpoints{8080, "/actuator/health", "/actuator/health", ""},
+			expectedOk: true,
+		},
+		{
+			name:       "probes enabled",
+			properties: map[string]string{"management.endpoint.health.probes.enabled": "true"},
+			expected:   HealthProbeEndpoints{8080, "/actuator/health/liveness", "/actuator/health/readiness", ""},
+			expectedOk: true,
+		},
+		{
+			name: "readiness group and context path",
+			properties: map[string]string{
+				"server.servlet.context-path":                        "/api",
+				"management.endpoint.health.group.readiness.include": "readinessState,db",
+			},
+			expected:   HealthProbeEndpoints{8080, "/api/actuator/health", "/api/actuator/health/readiness", ""},
+			expectedOk: true,
+		},
+		{
+			name:       "webflux base path",
+			properties: map[string]string{"spring.webflux.base-path": "/web"},
+			reactive:   true,
+			expected:   HealthProbeEndpoints{8080, "/web/actuator/health", "/web/actuator/health", ""},
+			expectedOk: true,
+		},
+		{
+			name: "management port",
+			properties: map[string]string{
+				"server.servlet.context-path":               "/api",
+				"MANAGEMENT_SERVER_PORT":                    "9090",
+				"management.server.base-path":               "/management",
+				"management.endpoints.web.base-path":        "/",
+				"management.endpoint.health.probes.enabled": "true",
+			},
+			expected:   HealthProbeEndpoints{9090, "/management/health/liveness", "/management/health/readiness", ""},
+			expectedOk: true,
+		},
+		{
+			name:       "health endpoint path mapping",
+			properties: map[string]string{"management.endpoints.web.path-mapping.health": "healthcheck"},
+			expected:   HealthProbeEndpoints{8080, "/actuator/healthcheck", "/actuator/healthcheck", ""},
+			expectedOk: true,
+		},
+		{
+			name:       "management endpoints disabled",
+			properties: map[string]string{"management.server.port": "-1"},
+		},
+		{
+			name:       "health endpoint disabled",
+			properties: map[string]string{"management.endpoint.health.enabled": "false"},
+		},
+		{
+			name:       "health endpoint not exposed",
+			properties: map[string]string{"management.endpoints.web.exposure.include": "info,metrics"},
+		},
+		{
+			name:       "health endpoint excluded",
+			properties: map[string]string{"management.endpoints.web.exposure.exclude": "*"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			endpoints, ok := GetHealthProbeEndpoints(NewTestPropertyValues(tt.properties), 8080, tt.reactive)
+			require.Equal(t, tt.expectedOk, ok)
+			require.Equal(t, tt.expected, endpoints)
+		})
+	}
+}
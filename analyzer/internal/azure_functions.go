@@ -0,0 +1,59 @@
+package internal
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+)
+
+// ReadFunctionsLocalSettings reads the app settings of Azure Functions projects, which are the "Values" in
+// local.settings.json used when running the functions locally. Nil is returned if the file doesn't exist.
+func ReadFunctionsLocalSettings(projectPath string) (PropertyValues, error) {
+	filePath := filepath.Join(projectPath, "local.settings.json")
+	content, err := os.ReadFile(filePath)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+	var settings struct {
+		Values map[string]string `json:"Values"`
+	}
+	if err := json.Unmarshal(content, &settings); err != nil {
+		return nil, &ConfigFileError{FilePath: filePath, Err: err}
+	}
+	names := make([]string, 0, len(settings.Values))
+	for name := range settings.Values {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	result := make(PropertyValues, len(settings.Values))
+	for _, name := range names {
+		result.Set(name, PropertyValue{Value: settings.Values[name], Origin: PropertyOrigin{FilePath: filePath}})
+	}
+	return result, nil
+}
+
+var functionsBindingExpressionRegex = regexp.MustCompile(`%([^%]+)%`)
+
+// ResolveFunctionsBindingExpressions resolves app setting references like "%QueueName%" in binding attribute values of
+// Azure Functions, by the settings then by environment variables. False is returned if any reference can not be
+// resolved.
+func ResolveFunctionsBindingExpressions(value string, settings PropertyValues) (string, bool) {
+	resolved := true
+	result := functionsBindingExpressionRegex.ReplaceAllStringFunc(value, func(match string) string {
+		name := match[1 : len(match)-1]
+		if setting, ok := settings.Get(name); ok {
+			return setting.Value
+		}
+		if environmentValue, ok := os.LookupEnv(name); ok {
+			return environmentValue
+		}
+		resolved = false
+		return match
+	})
+	return result, resolved
+}
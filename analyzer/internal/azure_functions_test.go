@@ -0,0 +1,60 @@
+package internal
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadFunctionsLocalSettings(t *testing.T) {
+	directory := t.TempDir()
+	settings, err := ReadFunctionsLocalSettings(directory)
+	require.NoError(t, err)
+	require.Nil(t, settings)
+
+	filePath := filepath.Join(directory, "local.settings.json")
+	require.NoError(t, os.WriteFile(filePath, []byte(`{
+		"IsEncrypted": false,
+		"Values": {
+			"FUNCTIONS_WORKER_RUNTIME": "java",
+			"OrdersQueue": "orders"
+		}
+	}`), 0600))
+	settings, err = ReadFunctionsLocalSettings(directory)
+	require.NoError(t, err)
+	require.Equal(t, PropertyValues{
+		"functions.worker.runtime": {Name: "FUNCTIONS_WORKER_RUNTIME", Value: "java",
+			Origin: PropertyOrigin{FilePath: filePath}},
+		"ordersqueue": {Name: "OrdersQueue", Value: "orders", Origin: PropertyOrigin{FilePath: filePath}},
+	}, settings)
+
+	require.NoError(t, os.WriteFile(filePath, []byte(`{"Values": [}`), 0600))
+	_, err = ReadFunctionsLocalSettings(directory)
+	var configFileError *ConfigFileError
+	require.ErrorAs(t, err, &configFileError)
+	require.Equal(t, filePath, configFileError.FilePath)
+}
+
+func TestResolveFunctionsBindingExpressions(t *testing.T) {
+	t.Setenv("ENVIRONMENT_NAME", "prod")
+	settings := map[string]string{"OrdersQueue": "orders"}
+	tests := []struct {
+		value            string
+		expected         string
+		expectedResolved bool
+	}{
+		{"payments", "payments", true},
+		{"%OrdersQueue%", "orders", true},
+		{"images-%ENVIRONMENT_NAME%/{name}", "images-prod/{name}", true},
+		{"%UnknownQueue%", "%UnknownQueue%", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			resolved, ok := ResolveFunctionsBindingExpressions(tt.value, NewTestPropertyValues(settings))
+			require.Equal(t, tt.expectedResolved, ok)
+			require.Equal(t, tt.expected, resolved)
+		})
+	}
+}
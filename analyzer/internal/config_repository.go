@@ -0,0 +1,46 @@
+package internal
+
+// defaultConfigServerApplicationName is used by Spring Cloud Config Client when spring.application.name is not set.
+const defaultConfigServerApplicationName = "application"
+
+// defaultConfigServerProfile is used by Spring Cloud Config Client when no profile is active.
+const defaultConfigServerProfile = "default"
+
+// loadConfigRepository loads the files served by Spring Cloud Config Server from its local repository, for the
+// application named by spring.application.name. Same as the config server, the files are ordered from low precedence
+// to high precedence:
+//  1. application.{yaml,yml,properties}
+//  2. {application}.{yaml,yml,properties}
+//  3. application-{profile}.{yaml,yml,properties} and {application}-{profile}.{yaml,yml,properties}, for each profile.
+//
+// The "default" profile is used if no profile is active.
+func (l *configDataLoader) loadConfigRepository(localProperties PropertyValues,
+	profiles []string) ([]propertyDocument, error) {
+	applicationName := defaultConfigServerApplicationName
+	if value, ok := localProperties.Get("spring.application.name"); ok && value.Value != "" {
+		applicationName = value.Value
+	}
+	if len(profiles) == 0 {
+		profiles = []string{defaultConfigServerProfile}
+	}
+	names := AppendAndDistinctInOrder([]string{defaultConfigServerApplicationName}, applicationName)
+	location := configDataLocation{l.configRepositoryPath, true}
+	var documents []propertyDocument
+	for _, name := range names {
+		nameDocuments, err := l.loadLocation(location, name, "")
+		if err != nil {
+			return nil, err
+		}
+		documents = append(documents, nameDocuments...)
+	}
+	for _, profile := range profiles {
+		for _, name := range names {
+			profileDocuments, err := l.loadLocation(location, name, profile)
+			if err != nil {
+				return nil, err
+			}
+			documents = append(documents, profileDocuments...)
+		}
+	}
+	return documents, nil
+}
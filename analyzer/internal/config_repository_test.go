@@ -0,0 +1,28 @@
+package internal
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadSpringBootConfigWithConfigRepository(t *testing.T) {
+	config, err := ReadSpringBootConfig(filepath.Join("testdata", "java-spring", "project-eight"),
+		SpringBootConfigOptions{ConfigRepositoryPath: filepath.Join("testdata", "config-repo")})
+	require.NoError(t, err)
+	properties := config.PropertyValues.ToMap()
+	require.Equal(t, "local", properties["app.local-only"])
+	require.Equal(t, "repository-application", properties["app.shared"])
+	require.Equal(t, "repository-customers-service", properties["app.source"])
+	require.Equal(t, "jdbc:mysql://localhost:3306/customers-db", properties["spring.datasource.url"])
+	require.Equal(t, []string{"configserver:http://localhost:8888/"}, config.ExternalConfigImports)
+}
+
+func TestReadSpringBootConfigWithConfigRepositoryAndDefaultApplicationName(t *testing.T) {
+	config, err := ReadSpringBootConfig(filepath.Join("testdata", "java-spring", "project-one"),
+		SpringBootConfigOptions{ConfigRepositoryPath: filepath.Join("testdata", "config-repo")})
+	require.NoError(t, err)
+	require.Equal(t, "repository-application", config.PropertyValues.ToMap()["app.source"])
+	require.Equal(t, "jdbc:h2:mem:testdb", config.PropertyValues.ToMap()["spring.datasource.url"])
+}
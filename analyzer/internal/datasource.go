@@ -0,0 +1,153 @@
+package internal
+
+import (
+	"net"
+	"strconv"
+	"strings"
+)
+
+// DatasourceURL is the database connection of a JDBC or R2DBC URL.
+type DatasourceURL struct {
+	// Vendor is like "postgresql", "mysql", "mariadb", "sqlserver", "oracle", "h2", "hsqldb" or "derby".
+	Vendor string
+	// Host is empty for embedded databases and URLs without a host. Port is 0 if not set.
+	Host string
+	Port int
+	// DatabaseName is as it's in the URL, which may not be a valid database name, see IsValidDatabaseName.
+	DatabaseName string
+}
+
+// datasourceURLVendors maps the sub-protocols of the URLs to the vendors.
+var datasourceURLVendors = map[string]string{
+	"postgresql": "postgresql",
+	"postgres":   "postgresql",
+	"mysql":      "mysql",
+	"mariadb":    "mariadb",
+	"sqlserver":  "sqlserver",
+	"mssql":      "sqlserver",
+	"oracle":     "oracle",
+	"h2":         "h2",
+	"hsqldb":     "hsqldb",
+	"derby":      "derby",
+}
+
+// IsEmbeddedDatabaseVendor checks whether the vendor is an embedded database like H2, which is not a backing service.
+func IsEmbeddedDatabaseVendor(vendor string) bool {
+	return vendor == "h2" || vendor == "hsqldb" || vendor == "derby"
+}
+
+// ParseDatasourceURL parses URLs like "jdbc:postgresql://localhost:5432/orders",
+// "r2dbc:mysql://user@localhost:3306/orders", "jdbc:sqlserver://localhost:1433;databaseName=orders" and
+// "jdbc:oracle:thin:@//localhost:1521/orders". False is returned if the URL is not a JDBC or R2DBC URL of a known
+// vendor.
+func ParseDatasourceURL(url string) (DatasourceURL, bool) {
+	scheme, rest, _ := strings.Cut(strings.TrimSpace(url), ":")
+	if scheme != "jdbc" && scheme != "r2dbc" {
+		return DatasourceURL{}, false
+	}
+	subProtocol, rest, _ := strings.Cut(rest, ":")
+	if scheme == "r2dbc" && subProtocol == "pool" {
+		subProtocol, rest, _ = strings.Cut(rest, ":")
+	}
+	vendor, ok := datasourceURLVendors[strings.ToLower(subProtocol)]
+	if !ok {
+		return DatasourceURL{}, false
+	}
+	result := DatasourceURL{Vendor: vendor}
+	switch {
+	case IsEmbeddedDatabaseVendor(vendor):
+	case scheme == "jdbc" && vendor == "sqlserver":
+		parseSqlServerURL(rest, &result)
+	case scheme == "jdbc" && vendor == "oracle":
+		parseOracleURL(rest, &result)
+	default:
+		parseHierarchicalURL(rest, &result)
+	}
+	return result, true
+}
+
+// parseHierarchicalURL parses the part after the sub-protocol like "//localhost:5432/orders?ssl=true", or like
+// "loadbalance://localhost:3306/orders" of MySQL. The first host is used if there are multiple hosts.
+func parseHierarchicalURL(rest string, result *DatasourceURL) {
+	if index := strings.Index(rest, "://"); index >= 0 && !strings.ContainsAny(rest[:index], "/?") {
+		rest = rest[index+1:]
+	}
+	if !strings.HasPrefix(rest, "//") {
+		// Like "jdbc:postgresql:orders", which connects to localhost.
+		result.DatabaseName = cutAny(rest, "?")
+		return
+	}
+	authority, path, _ := strings.Cut(strings.TrimPrefix(rest, "//"), "/")
+	authority, _, _ = strings.Cut(authority, "?")
+	setHostAndPort(authority, result)
+	result.DatabaseName = cutAny(path, "?;")
+}
+
+// parseSqlServerURL parses the part after the sub-protocol like "//localhost\instance:1433;databaseName=orders". The
+// host and port can also be set by the serverName and portNumber properties.
+func parseSqlServerURL(rest string, result *DatasourceURL) {
+	authority, connectionProperties, _ := strings.Cut(strings.TrimPrefix(rest, "//"), ";")
+	if host, _, ok := strings.Cut(authority, "\\"); ok {
+		// The port follows the instance name.
+		_, port, _ := strings.Cut(authority, ":")
+		authority = net.JoinHostPort(host, port)
+	}
+	setHostAndPort(authority, result)
+	for _, property := range strings.Split(connectionProperties, ";") {
+		key, value, _ := strings.Cut(property, "=")
+		key, value = strings.ToLower(strings.TrimSpace(key)), strings.TrimSpace(value)
+		switch {
+		case key == "databasename" || key == "database":
+			result.DatabaseName = value
+		case key == "servername" && result.Host == "":
+			result.Host = value
+		case key == "portnumber" && result.Port == 0:
+			result.Port, _ = strconv.Atoi(value)
+		}
+	}
+}
+
+// parseOracleURL parses the part after the sub-protocol like "thin:@localhost:1521:ORCL" with the SID, or like
+// "thin:@//localhost:1521/orders" with the service name. TNS descriptors like "thin:@(DESCRIPTION=...)" are not
+// parsed.
+func parseOracleURL(rest string, result *DatasourceURL) {
+	_, address, ok := strings.Cut(rest, "@")
+	if !ok || strings.HasPrefix(address, "(") {
+		return
+	}
+	address = strings.TrimPrefix(address, "//")
+	authority, serviceName, ok := strings.Cut(address, "/")
+	if !ok {
+		parts := strings.Split(address, ":")
+		authority = strings.Join(parts[:min(2, len(parts))], ":")
+		if len(parts) > 2 {
+			serviceName = parts[2]
+		}
+	}
+	setHostAndPort(authority, result)
+	result.DatabaseName = cutAny(serviceName, "?")
+}
+
+// setHostAndPort sets the host and port of an authority like "user:password@localhost:5432". The first host is used
+// if there are multiple hosts like "primary:3306,replica:3306".
+func setHostAndPort(authority string, result *DatasourceURL) {
+	if index := strings.LastIndex(authority, "@"); index >= 0 {
+		authority = authority[index+1:]
+	}
+	authority, _, _ = strings.Cut(authority, ",")
+	host, port, err := net.SplitHostPort(authority)
+	if err != nil {
+		result.Host = authority
+		return
+	}
+	result.Host = host
+	result.Port, _ = strconv.Atoi(port)
+}
+
+// cutAny gets the part of the value before any of the separators.
+func cutAny(value string, separators string) string {
+	if index := strings.IndexAny(value, separators); index >= 0 {
+		return value[:index]
+	}
+	return value
+}
@@ -0,0 +1,360 @@
+package internal
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/braydonk/yaml"
+)
+
+// kubernetesManifestDirectoryNames are the directories searched for Kubernetes manifests, like k8s/*.yaml.
+var kubernetesManifestDirectoryNames = []string{"k8s", "kubernetes"}
+
+// helmTemplateRegex matches Helm template actions like "{{ .Values.image }}" or "{{- include "app.labels" . }}".
+var helmTemplateRegex = regexp.MustCompile(`(?s)\{\{.*?\}\}`)
+
+// errHelmTemplate is returned when a Kubernetes manifest is a Helm template. Its values are only known after the
+// template is rendered, so it's skipped no matter whether SkipMalformedFiles is enabled.
+var errHelmTemplate = errors.New("Kubernetes manifest is a Helm template")
+
+type kubernetesObject struct {
+	Kind     string             `yaml:"kind"`
+	Metadata kubernetesMetadata `yaml:"metadata"`
+	Data     map[string]string  `yaml:"data"`
+	Spec     struct {
+		Template struct {
+			Metadata kubernetesMetadata `yaml:"metadata"`
+			Spec     kubernetesPodSpec  `yaml:"spec"`
+		} `yaml:"template"`
+	} `yaml:"spec"`
+	filePath string
+}
+
+type kubernetesMetadata struct {
+	Name   string            `yaml:"name"`
+	Labels map[string]string `yaml:"labels"`
+}
+
+type kubernetesPodSpec struct {
+	Containers []kubernetesContainer `yaml:"containers"`
+	Volumes    []struct {
+		ConfigMap struct {
+			Name string `yaml:"name"`
+		} `yaml:"configMap"`
+	} `yaml:"volumes"`
+}
+
+type kubernetesContainer struct {
+	Name string `yaml:"name"`
+	Env  []struct {
+		Name  string `yaml:"name"`
+		Value string `yaml:"value"`
+		// ValueFrom is nil for literal values, its ConfigMapKeyRef is nil for values from Secrets or fields.
+		ValueFrom *struct {
+			ConfigMapKeyRef *struct {
+				Name string `yaml:"name"`
+				Key  string `yaml:"key"`
+			} `yaml:"configMapKeyRef"`
+		} `yaml:"valueFrom"`
+	} `yaml:"env"`
+	EnvFrom []struct {
+		Prefix       string `yaml:"prefix"`
+		ConfigMapRef struct {
+			Name string `yaml:"name"`
+		} `yaml:"configMapRef"`
+	} `yaml:"envFrom"`
+}
+
+// loadDeploymentConfig loads the config used when deploying the application, which is the lowest precedence property
+// source. The files are searched in each directory of DeploymentConfigSearchPaths, ordered from low precedence to high
+// precedence:
+//  1. .env files.
+//  2. Kubernetes ConfigMaps in k8s/*.yaml named after the application, which is the convention of Spring Cloud
+//     Kubernetes. Keys like "application.yml" are read as config files, other keys are read as properties.
+//  3. Config files in the ConfigMaps mounted as volumes by the application's Deployments.
+//  4. Environment variables of the application's Deployments, from "envFrom" then "env".
+//
+// A Deployment belongs to the application if its name, its "app" label or one of its container names equals to one
+// of the application names. Environment variable names like SPRING_DATASOURCE_URL are converted to property names by
+// relaxed binding.
+func (l *configDataLoader) loadDeploymentConfig(applicationNames []string) ([]propertyDocument, error) {
+	var documents []propertyDocument
+	var objects []kubernetesObject
+	for _, searchPath := range l.deploymentConfigSearchPaths {
+		document, err := l.loadDotEnvFile(filepath.Join(searchPath, ".env"))
+		if err != nil {
+			return nil, err
+		}
+		if document != nil {
+			documents = append(documents, propertyDocument{values: document})
+		}
+		for _, directoryName := range kubernetesManifestDirectoryNames {
+			directoryObjects, err := l.loadKubernetesObjects(filepath.Join(searchPath, directoryName))
+			if err != nil {
+				return nil, err
+			}
+			objects = append(objects, directoryObjects...)
+		}
+	}
+	configMaps := make(map[string]kubernetesObject)
+	var deployments []kubernetesObject
+	for _, object := range objects {
+		switch object.Kind {
+		case "ConfigMap":
+			configMaps[object.Metadata.Name] = object
+		case "Deployment":
+			if isDeploymentOfApplication(object, applicationNames) {
+				deployments = append(deployments, object)
+			}
+		}
+	}
+	for _, name := range applicationNames {
+		if configMap, ok := configMaps[name]; ok {
+			configMapDocuments, err := l.loadConfigMapData(configMap, true)
+			if err != nil {
+				return nil, err
+			}
+			documents = append(documents, configMapDocuments...)
+		}
+	}
+	for _, deployment := range deployments {
+		for _, volume := range deployment.Spec.Template.Spec.Volumes {
+			if configMap, ok := configMaps[volume.ConfigMap.Name]; ok {
+				configMapDocuments, err := l.loadConfigMapData(configMap, false)
+				if err != nil {
+					return nil, err
+				}
+				documents = append(documents, configMapDocuments...)
+			}
+		}
+	}
+	for _, deployment := range deployments {
+		for _, container := range deployment.Spec.Template.Spec.Containers {
+			documents = append(documents,
+				propertyDocument{values: getContainerEnvironmentVariables(deployment, container, configMaps)})
+		}
+	}
+	return documents, nil
+}
+
+func isDeploymentOfApplication(deployment kubernetesObject, applicationNames []string) bool {
+	names := []string{
+		deployment.Metadata.Name,
+		deployment.Metadata.Labels["app"],
+		deployment.Spec.Template.Metadata.Labels["app"],
+	}
+	for _, container := range deployment.Spec.Template.Spec.Containers {
+		names = append(names, container.Name)
+	}
+	for _, name := range names {
+		if name != "" && slices.Contains(applicationNames, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// loadConfigMapData loads the ConfigMap's data. Keys like "application.yml" are read as config files. Other keys are
+// read as properties only if includeProperties is true.
+func (l *configDataLoader) loadConfigMapData(configMap kubernetesObject,
+	includeProperties bool) ([]propertyDocument, error) {
+	properties := make(PropertyValues)
+	var documents []propertyDocument
+	keys := make([]string, 0, len(configMap.Data))
+	for key := range configMap.Data {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		value := configMap.Data[key]
+		filePath := getConfigMapEntryDescription(configMap, key)
+		var keyDocuments []propertyDocument
+		var err error
+		switch strings.ToLower(filepath.Ext(key)) {
+		case ".properties":
+			keyDocuments, err = readPropertiesInPropertiesContent(filePath, strings.NewReader(value))
+		case ".yaml", ".yml":
+			keyDocuments, err = readPropertiesInYamlContent(filePath, []byte(value))
+		default:
+			if includeProperties {
+				properties.Set(toPropertyName(key), PropertyValue{
+					Value:  getEnvironmentVariablePlaceholderHandledValue(value),
+					Origin: PropertyOrigin{FilePath: filePath},
+				})
+			}
+			continue
+		}
+		if err = l.skipOrReturnError(err); err != nil {
+			return nil, err
+		}
+		documents = append(documents, keyDocuments...)
+	}
+	return append([]propertyDocument{{values: properties}}, documents...), nil
+}
+
+func getConfigMapEntryDescription(configMap kubernetesObject, key string) string {
+	return fmt.Sprintf("%s (ConfigMap %s, key %s)", configMap.filePath, configMap.Metadata.Name, key)
+}
+
+// getContainerEnvironmentVariables gets the environment variables of the container. Variables which can't be resolved,
+// like the ones from Secrets, fields or missing ConfigMaps, are skipped, so they don't override the lower precedence
+// property sources.
+func getContainerEnvironmentVariables(deployment kubernetesObject, container kubernetesContainer,
+	configMaps map[string]kubernetesObject) PropertyValues {
+	result := make(PropertyValues)
+	for _, envFrom := range container.EnvFrom {
+		configMap, ok := configMaps[envFrom.ConfigMapRef.Name]
+		if !ok {
+			continue
+		}
+		keys := make([]string, 0, len(configMap.Data))
+		for key := range configMap.Data {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			if isEnvironmentVariableStyleName(key) {
+				result.Set(toPropertyName(envFrom.Prefix+key), PropertyValue{
+					Value:  configMap.Data[key],
+					Origin: PropertyOrigin{FilePath: getConfigMapEntryDescription(configMap, key)},
+				})
+			}
+		}
+	}
+	for _, env := range container.Env {
+		value := env.Value
+		origin := PropertyOrigin{FilePath: fmt.Sprintf("%s (Deployment %s, container %s, env %s)",
+			deployment.filePath, deployment.Metadata.Name, container.Name, env.Name)}
+		if env.ValueFrom != nil {
+			reference := env.ValueFrom.ConfigMapKeyRef
+			if reference == nil {
+				continue
+			}
+			configMapValue, ok := configMaps[reference.Name].Data[reference.Key]
+			if !ok {
+				continue
+			}
+			value = configMapValue
+			origin.FilePath = getConfigMapEntryDescription(configMaps[reference.Name], reference.Key)
+		}
+		result.Set(toPropertyName(env.Name), PropertyValue{Value: value, Origin: origin})
+	}
+	return result
+}
+
+// toPropertyName converts environment variable names like SPRING_DATASOURCE_URL to property names like
+// spring.datasource.url. Other names are kept.
+func toPropertyName(name string) string {
+	if isEnvironmentVariableStyleName(name) {
+		return CanonicalPropertyName(name)
+	}
+	return name
+}
+
+func (l *configDataLoader) loadKubernetesObjects(directory string) ([]kubernetesObject, error) {
+	var filePaths []string
+	err := filepath.WalkDir(directory, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		extension := strings.ToLower(filepath.Ext(path))
+		if !entry.IsDir() && (extension == ".yaml" || extension == ".yml") {
+			filePaths = append(filePaths, path)
+		}
+		return nil
+	})
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("walking directory %s: %w", directory, err)
+	}
+	var result []kubernetesObject
+	for _, filePath := range filePaths {
+		objects, err := readKubernetesObjects(filePath)
+		if errors.Is(err, errHelmTemplate) {
+			l.skippedTemplates = append(l.skippedTemplates, filePath)
+			continue
+		}
+		if err = l.skipOrReturnError(err); err != nil {
+			return nil, err
+		}
+		result = append(result, objects...)
+	}
+	return result, nil
+}
+
+func readKubernetesObjects(filePath string) ([]kubernetesObject, error) {
+	data, err := os.ReadFile(filePath)
+	if err != nil {
+		return nil, &ConfigFileError{FilePath: filePath, Err: fmt.Errorf("reading Kubernetes manifest: %w", err)}
+	}
+	if helmTemplateRegex.Match(data) {
+		return nil, errHelmTemplate
+	}
+	var result []kubernetesObject
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	for {
+		var object kubernetesObject
+		err := decoder.Decode(&object)
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, newYamlFileError(filePath, err)
+		}
+		object.filePath = filePath
+		result = append(result, object)
+	}
+	return result, nil
+}
+
+// loadDotEnvFile reads lines like "KEY=VALUE" or "export KEY='VALUE'". Nil is returned if the file doesn't exist.
+func (l *configDataLoader) loadDotEnvFile(filePath string) (PropertyValues, error) {
+	file, err := os.Open(filePath)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, l.skipOrReturnError(
+			&ConfigFileError{FilePath: filePath, Err: fmt.Errorf("opening .env file: %w", err)})
+	}
+	defer file.Close()
+	result := make(PropertyValues)
+	scanner := bufio.NewScanner(file)
+	lineNumber := 0
+	for scanner.Scan() {
+		lineNumber++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		parts := strings.SplitN(strings.TrimPrefix(line, "export "), "=", 2)
+		if len(parts) != 2 {
+			continue
+		}
+		value := strings.TrimSpace(parts[1])
+		if len(value) >= 2 && (value[0] == '"' || value[0] == '\'') && value[len(value)-1] == value[0] {
+			value = value[1 : len(value)-1]
+		}
+		result.Set(toPropertyName(strings.TrimSpace(parts[0])), PropertyValue{
+			Value:  value,
+			Origin: PropertyOrigin{FilePath: filePath, Line: lineNumber},
+		})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, l.skipOrReturnError(&ConfigFileError{FilePath: filePath, Line: lineNumber + 1,
+			Err: fmt.Errorf("scanning .env file: %w", err)})
+	}
+	return result, nil
+}
@@ -0,0 +1,47 @@
+package internal
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadSpringBootConfigWithDeploymentConfig(t *testing.T) {
+	rootPath := filepath.Join("testdata", "deployment-config")
+	projectPath := filepath.Join(rootPath, "orders")
+	config, err := ReadSpringBootConfig(projectPath, SpringBootConfigOptions{
+		DeploymentConfigSearchPaths: []string{rootPath, projectPath},
+	})
+	require.NoError(t, err)
+	properties := config.PropertyValues.ToMap()
+	require.Equal(t, "jdbc:postgresql://localhost:5432/local-db", properties["spring.datasource.url"])
+	require.Equal(t, "dotenv", properties["app.dotenv.only"])
+	require.Equal(t, "deployment", properties["app.deployment.env"])
+	require.Equal(t, "config-map", properties["app.config-map-file"])
+	require.Equal(t, "k8s", properties["app.profile"])
+	require.Equal(t, "", properties["app.other"])
+	require.Equal(t, "password-from-dotenv", properties["spring.datasource.password"])
+	require.Equal(t, "pod-from-dotenv", properties["app.pod.name"])
+	require.Equal(t, "region-from-dotenv", properties["app.region"])
+	hub, ok := GetPropertyValue(config.PropertyValues, "spring.cloud.azure.eventhubs.event-hub-name")
+	require.True(t, ok)
+	require.Equal(t, "hub-from-deployment", hub)
+	require.Equal(t, []string{"queue-from-config-map"}, GetDistinctBindingDestinationValues(config.PropertyValues))
+	require.Equal(t, []string{filepath.Join(rootPath, "k8s", "chart", "templates", "deployment.yaml")},
+		config.SkippedTemplates)
+	require.Empty(t, config.SkippedFiles)
+}
+
+func TestReadSpringBootConfigWithoutDeploymentConfig(t *testing.T) {
+	properties, err := ReadProperties(filepath.Join("testdata", "deployment-config", "orders"))
+	require.NoError(t, err)
+	require.Equal(t, "", properties["app.dotenv.only"])
+	require.Equal(t, "", properties["app.profile"])
+}
+
+func TestToPropertyName(t *testing.T) {
+	require.Equal(t, "spring.datasource.url", toPropertyName("SPRING_DATASOURCE_URL"))
+	require.Equal(t, "spring.datasource.url", toPropertyName("spring.datasource.url"))
+	require.Equal(t, "my.list[0].name", toPropertyName("MY_LIST_0_NAME"))
+}
@@ -0,0 +1,64 @@
+package internal
+
+import (
+	"os"
+	"strings"
+)
+
+// DefaultQuarkusProfile is the profile used by Quarkus when running the packaged application.
+const DefaultQuarkusProfile = "prod"
+
+// GetQuarkusProfile gets the profile of Quarkus applications from QUARKUS_PROFILE, and defaults to
+// DefaultQuarkusProfile.
+func GetQuarkusProfile() string {
+	if profile := strings.TrimSpace(os.Getenv("QUARKUS_PROFILE")); profile != "" {
+		return profile
+	}
+	return DefaultQuarkusProfile
+}
+
+// ApplyQuarkusProfile applies the profile-specific properties of Quarkus, which are prefixed by the profiles like
+// "%prod.quarkus.http.port" or "%dev,test.quarkus.http.port". Properties of the profile take precedence over the ones
+// without prefix, and properties of other profiles are removed.
+func ApplyQuarkusProfile(values PropertyValues, profile string) PropertyValues {
+	result := make(PropertyValues)
+	var profileValues []PropertyValue
+	for _, canonicalName := range values.sortedCanonicalNames() {
+		value := values[canonicalName]
+		if strings.HasPrefix(value.Name, "%") {
+			profileValues = append(profileValues, value)
+		} else {
+			result.Set(value.Name, value)
+		}
+	}
+	for _, value := range profileValues {
+		profiles, unprefixedName, ok := strings.Cut(strings.TrimPrefix(value.Name, "%"), ".")
+		if !ok || !containsProfile(profiles, profile) {
+			continue
+		}
+		value.Origin.Profile = profile
+		result.Set(unprefixedName, value)
+	}
+	return result
+}
+
+func containsProfile(profiles string, profile string) bool {
+	for _, p := range strings.Split(profiles, ",") {
+		if strings.TrimSpace(p) == profile {
+			return true
+		}
+	}
+	return false
+}
+
+// GetMicronautEnvironments gets the environments of Micronaut applications from MICRONAUT_ENVIRONMENTS. Same as
+// profiles of Spring Boot, they activate files like application-{environment}.yml.
+func GetMicronautEnvironments() []string {
+	var result []string
+	for _, environment := range strings.Split(os.Getenv("MICRONAUT_ENVIRONMENTS"), ",") {
+		if environment = strings.TrimSpace(environment); environment != "" {
+			result = append(result, environment)
+		}
+	}
+	return result
+}
@@ -0,0 +1,52 @@
+package internal
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestApplyQuarkusProfile(t *testing.T) {
+	config, err := ReadSpringBootConfig(filepath.Join("testdata", "java-spring", "project-eleven"),
+		SpringBootConfigOptions{Profiles: []string{DefaultQuarkusProfile}})
+	require.NoError(t, err)
+	values := ApplyQuarkusProfile(config.PropertyValues, DefaultQuarkusProfile)
+	require.Equal(t, map[string]string{
+		"quarkus.http.port":           "8081",
+		"quarkus.datasource.db-kind":  "postgresql",
+		"quarkus.datasource.jdbc.url": "jdbc:postgresql://orders-db:5432/orders",
+	}, values.ToMap())
+	url := values["quarkus.datasource.jdbc.url"]
+	require.Equal(t, "prod", url.Origin.Profile)
+	require.Equal(t, 4, url.Origin.Line)
+	require.Len(t, url.Overrides, 1)
+	require.Equal(t, "jdbc:postgresql://localhost:5432/dev-orders", url.Overrides[0].Value)
+
+	require.Equal(t, "8082", ApplyQuarkusProfile(config.PropertyValues, "dev")["quarkus.http.port"].Value)
+}
+
+func TestApplyQuarkusProfileWithMultipleProfiles(t *testing.T) {
+	values := NewTestPropertyValues(map[string]string{
+		"greeting":               "hello",
+		"%dev,test.greeting":     "hi",
+		"%staging.greeting":      "hey",
+		"%dev.quarkus.log.level": "DEBUG",
+	})
+	require.Equal(t, map[string]string{"greeting": "hi"}, ApplyQuarkusProfile(values, "test").ToMap())
+	require.Equal(t, map[string]string{"greeting": "hello"}, ApplyQuarkusProfile(values, "prod").ToMap())
+}
+
+func TestGetQuarkusProfile(t *testing.T) {
+	t.Setenv("QUARKUS_PROFILE", "")
+	require.Equal(t, "prod", GetQuarkusProfile())
+	t.Setenv("QUARKUS_PROFILE", "staging")
+	require.Equal(t, "staging", GetQuarkusProfile())
+}
+
+func TestGetMicronautEnvironments(t *testing.T) {
+	t.Setenv("MICRONAUT_ENVIRONMENTS", "")
+	require.Nil(t, GetMicronautEnvironments())
+	t.Setenv("MICRONAUT_ENVIRONMENTS", "cloud, azure")
+	require.Equal(t, []string{"cloud", "azure"}, GetMicronautEnvironments())
+}
@@ -0,0 +1,91 @@
+package internal
+
+import (
+	"path"
+	"strconv"
+	"strings"
+)
+
+// DefaultQuarkusManagementPort is the port of the Quarkus management interface if quarkus.management.port is not set.
+const DefaultQuarkusManagementPort = 9000
+
+// GetQuarkusHealthProbeEndpoints gets the health endpoints served by SmallRye Health of Quarkus. httpPort is the
+// application's HTTP port.
+//
+// The endpoints are served on the management interface if quarkus.management.enabled is true, whose root path is
+// quarkus.management.root-path and defaults to "q". Otherwise, they are served on the HTTP port under
+// quarkus.http.non-application-root-path, which defaults to "q" and is relative to quarkus.http.root-path. The health
+// endpoint is quarkus.smallrye-health.root-path and defaults to "health", and the probes use its "live", "ready" and
+// "started" sub-paths. Same as Quarkus, paths starting with "/" are absolute.
+//
+// False is returned if the health endpoints are disabled.
+func GetQuarkusHealthProbeEndpoints(properties PropertyValues, httpPort int) (HealthProbeEndpoints, bool) {
+	if isFalse(properties, "quarkus.smallrye-health.enabled") {
+		return HealthProbeEndpoints{}, false
+	}
+	port := httpPort
+	var rootPath string
+	if isTrue(properties, "quarkus.management.enabled") {
+		port = GetPort(properties, "quarkus.management.port", DefaultQuarkusManagementPort)
+		rootPath = resolveQuarkusPath("/", getPropertyValueOrDefault(properties, "quarkus.management.root-path", "q"))
+	} else {
+		rootPath = resolveQuarkusPath(getPropertyValueOrDefault(properties, "quarkus.http.root-path", "/"),
+			getPropertyValueOrDefault(properties, "quarkus.http.non-application-root-path", "q"))
+	}
+	healthPath := resolveQuarkusPath(rootPath,
+		getPropertyValueOrDefault(properties, "quarkus.smallrye-health.root-path", "health"))
+	return HealthProbeEndpoints{
+		Port: port,
+		LivenessPath: resolveQuarkusPath(healthPath,
+			getPropertyValueOrDefault(properties, "quarkus.smallrye-health.liveness-path", "live")),
+		ReadinessPath: resolveQuarkusPath(healthPath,
+			getPropertyValueOrDefault(properties, "quarkus.smallrye-health.readiness-path", "ready")),
+		StartupPath: resolveQuarkusPath(healthPath,
+			getPropertyValueOrDefault(properties, "quarkus.smallrye-health.startup-path", "started")),
+	}, true
+}
+
+// resolveQuarkusPath resolves the path relative to the base path, unless it's absolute.
+func resolveQuarkusPath(basePath string, value string) string {
+	if strings.HasPrefix(value, "/") {
+		return path.Clean(value)
+	}
+	return path.Join("/", basePath, value)
+}
+
+// GetMicronautHealthProbeEndpoints gets the health endpoints served by Micronaut management. serverPort is the
+// application's HTTP port.
+//
+// The endpoints are served on endpoints.all.port if it's set, otherwise on the server port with
+// micronaut.server.context-path. The health endpoint is {endpoints.all.path}/health, and the probes use its "liveness"
+// and "readiness" sub-paths.
+//
+// False is returned if the health endpoint is disabled.
+func GetMicronautHealthProbeEndpoints(properties PropertyValues, serverPort int) (HealthProbeEndpoints, bool) {
+	if isFalse(properties, "endpoints.health.enabled") || isFalse(properties, "endpoints.all.enabled") {
+		return HealthProbeEndpoints{}, false
+	}
+	port := serverPort
+	if value, ok := GetPropertyValue(properties, "endpoints.all.port"); ok {
+		if managementPort, err := strconv.Atoi(strings.TrimSpace(value)); err == nil && managementPort > 0 {
+			port = managementPort
+		}
+	}
+	var contextPath string
+	if port == serverPort {
+		contextPath = GetFirstPropertyValue(properties, "micronaut.server.context-path")
+	}
+	healthPath := path.Join("/", contextPath, GetFirstPropertyValue(properties, "endpoints.all.path"), "health")
+	return HealthProbeEndpoints{
+		Port:          port,
+		LivenessPath:  path.Join(healthPath, "liveness"),
+		ReadinessPath: path.Join(healthPath, "readiness"),
+	}, true
+}
+
+func getPropertyValueOrDefault(properties PropertyValues, name string, defaultValue string) string {
+	if value := GetFirstPropertyValue(properties, name); value != "" {
+		return value
+	}
+	return defaultValue
+}
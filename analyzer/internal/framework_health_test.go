@@ -0,0 +1,115 @@
+package internal
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetQuarkusHealthProbeEndpoints(t *testing.T) {
+	tests := []struct {
+		name       string
+		properties map[string]string
+		expected   HealthProbeEndpoints
+		expectedOk bool
+	}{
+		{
+			name:       "default",
+			properties: nil,
+			expected: HealthProbeEndpoints{Port: 8080, LivenessPath: "/q/health/live",
+				ReadinessPath: "/q/health/ready", StartupPath: "/q/health/started"},
+			expectedOk: true,
+		},
+		{
+			name: "root paths",
+			properties: map[string]string{
+				"quarkus.http.root-path":                "/api",
+				"quarkus.smallrye-health.root-path":     "status",
+				"quarkus.smallrye-health.liveness-path": "/alive",
+			},
+			expected: HealthProbeEndpoints{Port: 8080, LivenessPath: "/alive",
+				ReadinessPath: "/api/q/status/ready", StartupPath: "/api/q/status/started"},
+			expectedOk: true,
+		},
+		{
+			name: "absolute non-application root path",
+			properties: map[string]string{
+				"quarkus.http.root-path":                 "/api",
+				"quarkus.http.non-application-root-path": "/",
+			},
+			expected: HealthProbeEndpoints{Port: 8080, LivenessPath: "/health/live",
+				ReadinessPath: "/health/ready", StartupPath: "/health/started"},
+			expectedOk: true,
+		},
+		{
+			name: "management interface",
+			properties: map[string]string{
+				"quarkus.management.enabled": "true",
+				"quarkus.http.root-path":     "/api",
+			},
+			expected: HealthProbeEndpoints{Port: 9000, LivenessPath: "/q/health/live",
+				ReadinessPath: "/q/health/ready", StartupPath: "/q/health/started"},
+			expectedOk: true,
+		},
+		{
+			name:       "disabled",
+			properties: map[string]string{"quarkus.smallrye-health.enabled": "false"},
+			expectedOk: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			endpoints, ok := GetQuarkusHealthProbeEndpoints(NewTestPropertyValues(tt.properties), 8080)
+			require.Equal(t, tt.expectedOk, ok)
+			require.Equal(t, tt.expected, endpoints)
+		})
+	}
+}
+
+func TestGetMicronautHealthProbeEndpoints(t *testing.T) {
+	tests := []struct {
+		name       string
+		properties map[string]string
+		expected   HealthProbeEndpoints
+		expectedOk bool
+	}{
+		{
+			name:       "default",
+			properties: nil,
+			expected:   HealthProbeEndpoints{Port: 8080, LivenessPath: "/health/liveness", ReadinessPath: "/health/readiness"},
+			expectedOk: true,
+		},
+		{
+			name: "context path and endpoints path",
+			properties: map[string]string{
+				"micronaut.server.context-path": "/orders",
+				"endpoints.all.path":            "/management",
+			},
+			expected: HealthProbeEndpoints{Port: 8080, LivenessPath: "/orders/management/health/liveness",
+				ReadinessPath: "/orders/management/health/readiness"},
+			expectedOk: true,
+		},
+		{
+			name: "endpoints port",
+			properties: map[string]string{
+				"micronaut.server.context-path": "/orders",
+				"endpoints.all.port":            "8085",
+			},
+			expected: HealthProbeEndpoints{Port: 8085, LivenessPath: "/health/liveness",
+				ReadinessPath: "/health/readiness"},
+			expectedOk: true,
+		},
+		{
+			name:       "disabled",
+			properties: map[string]string{"endpoints.health.enabled": "false"},
+			expectedOk: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			endpoints, ok := GetMicronautHealthProbeEndpoints(NewTestPropertyValues(tt.properties), 8080)
+			require.Equal(t, tt.expectedOk, ok)
+			require.Equal(t, tt.expected, endpoints)
+		})
+	}
+}
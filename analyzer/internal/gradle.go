@@ -0,0 +1,232 @@
+package internal
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+var gradleBuildFileNames = []string{"build.gradle", "build.gradle.kts"}
+
+// readGradleBuildFile reads build.gradle or build.gradle.kts in the project directory, comments are removed.
+func readGradleBuildFile(projectPath string) (string, bool) {
+	for _, fileName := range gradleBuildFileNames {
+		content, err := os.ReadFile(filepath.Join(projectPath, fileName))
+		if err == nil {
+			return removeGradleComments(string(content)), true
+		}
+	}
+	return "", false
+}
+
+var gradleBlockCommentRegex = regexp.MustCompile(`(?s)/\*.*?\*/`)
+var gradleLineCommentRegex = regexp.MustCompile(`(?m)(^|\s)//.*$`)
+
+func removeGradleComments(content string) string {
+	content = gradleBlockCommentRegex.ReplaceAllString(content, "")
+	return gradleLineCommentRegex.ReplaceAllString(content, "$1")
+}
+
+// GetGradleResourceDirectories gets the resource directories of the main source set declared in the Gradle build
+// file. Both Groovy and Kotlin DSL are supported, for example:
+//
+//	sourceSets {
+//	    main {
+//	        resources {
+//	            srcDirs = ['src/main/resources', 'src/main/config']
+//	        }
+//	    }
+//	}
+//	sourceSets["main"].resources.srcDir("src/main/config")
+//
+// The default directory src/main/resources is included unless the directories are replaced by an assignment like
+// "srcDirs = [...]" or "setSrcDirs(...)". Returned directories are absolute if projectPath is absolute. Nil is
+// returned if no Gradle build file exists.
+func GetGradleResourceDirectories(projectPath string) []string {
+	content, ok := readGradleBuildFile(projectPath)
+	if !ok {
+		return nil
+	}
+	directories, replaceDefault := parseGradleMainResourceDirectories(content)
+	if !replaceDefault {
+		directories = append([]string{filepath.Join("src", "main", "resources")}, directories...)
+	}
+	var result []string
+	for _, directory := range directories {
+		if !filepath.IsAbs(directory) {
+			directory = filepath.Join(projectPath, directory)
+		}
+		result = AppendAndDistinctInOrder(result, directory)
+	}
+	return result
+}
+
+var gradleSourceSetsRegex = regexp.MustCompile(`\bsourceSets\s*(\{|\.|\[)`)
+var gradleMainSourceSetRegex = regexp.MustCompile(
+	`(\bmain|named\(\s*["']main["']\s*\)|getByName\(\s*["']main["']\s*\)|\[\s*["']main["']\s*\]|` +
+		`val\s+main\s+by\s+getting)\s*(\{|\.)`)
+var gradleResourcesRegex = regexp.MustCompile(`\bresources\s*(\{|\.)`)
+var gradleSrcDirRegex = regexp.MustCompile(`\b(setSrcDirs|srcDirs|srcDir)\b\s*(=)?`)
+var quotedStringRegex = regexp.MustCompile(`["']([^"']+)["']`)
+
+func parseGradleMainResourceDirectories(content string) (directories []string, replaceDefault bool) {
+	for _, sourceSets := range getGradleSnippets(content, gradleSourceSetsRegex) {
+		for _, main := range getGradleSnippets(sourceSets, gradleMainSourceSetRegex) {
+			for _, resources := range getGradleSnippets(main, gradleResourcesRegex) {
+				for _, match := range gradleSrcDirRegex.FindAllStringSubmatchIndex(resources, -1) {
+					method := resources[match[2]:match[3]]
+					isAssignment := match[4] != -1
+					if isAssignment || method == "setSrcDirs" {
+						replaceDefault = true
+						directories = nil
+					}
+					for _, quoted := range quotedStringRegex.FindAllStringSubmatch(
+						getGradleStatement(resources[match[1]:]), -1) {
+						directories = append(directories, quoted[1])
+					}
+				}
+			}
+		}
+	}
+	return directories, replaceDefault
+}
+
+// getGradleSnippets gets the snippets following each match of the regex. The last group of the regex must match
+// "{", "." or "[". For "{", the snippet is the content of the block. Otherwise, the snippet is the rest of the line.
+func getGradleSnippets(content string, regex *regexp.Regexp) []string {
+	var result []string
+	for _, match := range regex.FindAllStringSubmatchIndex(content, -1) {
+		delimiterStart := match[len(match)-2]
+		if content[delimiterStart] == '{' {
+			result = append(result, getBalancedContent(content[delimiterStart:], '{', '}'))
+		} else {
+			result = append(result, getGradleLine(content[delimiterStart:]))
+		}
+	}
+	return result
+}
+
+// getGradleStatement gets the arguments of a statement like "= ['a', 'b']", "('a', 'b')", "listOf('a')" or "'a'",
+// which can span multiple lines if brackets are used.
+func getGradleStatement(content string) string {
+	trimmed := strings.TrimLeft(content, " \t=")
+	if index := strings.IndexAny(trimmed, "[(\n"); index != -1 && strings.TrimSpace(trimmed[:index]) != "" &&
+		trimmed[index] == '(' {
+		// Function call like listOf("a") or files("a").
+		trimmed = trimmed[index:]
+	}
+	switch {
+	case strings.HasPrefix(trimmed, "["):
+		return getBalancedContent(trimmed, '[', ']')
+	case strings.HasPrefix(trimmed, "("):
+		return getBalancedContent(trimmed, '(', ')')
+	default:
+		return getGradleLine(trimmed)
+	}
+}
+
+// getGradleLine gets the content until the end of the line. Line breaks inside brackets don't end the line.
+func getGradleLine(content string) string {
+	depth := 0
+	for i := 0; i < len(content); i++ {
+		switch content[i] {
+		case '(', '[':
+			depth++
+		case ')', ']':
+			depth--
+		case '\n':
+			if depth <= 0 {
+				return content[:i]
+			}
+		}
+	}
+	return content
+}
+
+// getBalancedContent gets the content between the open character at the start of the string and its matching close
+// character.
+func getBalancedContent(content string, open byte, close byte) string {
+	depth := 0
+	for i := 0; i < len(content); i++ {
+		switch content[i] {
+		case open:
+			depth++
+		case close:
+			depth--
+			if depth == 0 {
+				return content[1:i]
+			}
+		}
+	}
+	return content[1:]
+}
+
+var gradlePluginRegex = regexp.MustCompile(
+	`\bid\s*\(?\s*["']([\w.\-]+)["']\s*\)?(?:\s*version\s*\(?\s*["']([^"']+)["']\s*\)?)?` +
+		`(\s*apply\s*\(?\s*false\b)?`)
+var gradleApplyPluginRegex = regexp.MustCompile(`\bapply\s*\(?\s*plugin\s*[:=]\s*["']([\w.\-]+)["']`)
+var gradleDependencyRegex = regexp.MustCompile(
+	`\b(implementation|api|compileOnly|runtimeOnly|developmentOnly|annotationProcessor|kapt|testImplementation|` +
+		`testRuntimeOnly)\s*\(?\s*(?:platform\s*\(\s*|enforcedPlatform\s*\(\s*)?["']([\w.\-]+):([\w.\-]+)` +
+		`(?::([^"'@:]+))?[^"']*["']`)
+
+// gradleCrossProjectBlockRegex matches the blocks configuring other projects, which don't apply to the project itself.
+var gradleCrossProjectBlockRegex = regexp.MustCompile(`\b(subprojects|allprojects)\s*\{`)
+
+// ReadGradleBuildModel reads the plugins and dependencies declared in build.gradle or build.gradle.kts as a Pom, so
+// Gradle projects are analyzed the same way as Maven projects. Plugins are recorded by their plugin marker
+// coordinates, which is "{id}:{id}.gradle.plugin". Dependencies of test configurations have the "test" scope, and
+// platforms like "platform('io.quarkus.platform:quarkus-bom:3.8.0')" are recorded in dependencyManagement. False is
+// returned if no Gradle build file exists.
+//
+// The build file is scanned by regular expressions instead of being evaluated by Gradle, so only the literal
+// declarations of the project itself are read. Plugins declared with "apply false" and the subprojects {} and
+// allprojects {} blocks are ignored. Plugins and dependencies from convention plugins, version catalogs like
+// "alias(libs.plugins.spring.boot)" or the root project's subprojects {} block are not found.
+func ReadGradleBuildModel(projectPath string) (Pom, bool) {
+	content, ok := readGradleBuildFile(projectPath)
+	if !ok {
+		return Pom{}, false
+	}
+	content = removeGradleBlocks(content, gradleCrossProjectBlockRegex)
+	result := Pom{ArtifactId: filepath.Base(projectPath)}
+	for _, match := range gradlePluginRegex.FindAllStringSubmatch(content, -1) {
+		if match[3] != "" {
+			continue
+		}
+		result.Build.Plugins = append(result.Build.Plugins, newGradlePlugin(match[1], match[2]))
+	}
+	for _, match := range gradleApplyPluginRegex.FindAllStringSubmatch(content, -1) {
+		result.Build.Plugins = append(result.Build.Plugins, newGradlePlugin(match[1], ""))
+	}
+	for _, match := range gradleDependencyRegex.FindAllStringSubmatch(content, -1) {
+		dep := dependency{GroupId: match[2], ArtifactId: match[3], Version: match[4]}
+		switch {
+		case strings.Contains(match[0], "latform("):
+			result.DependencyManagement.Dependencies = append(result.DependencyManagement.Dependencies, dep)
+			continue
+		case strings.HasPrefix(match[1], "test"):
+			dep.Scope = "test"
+		}
+		result.Dependencies = append(result.Dependencies, dep)
+	}
+	return result, true
+}
+
+// removeGradleBlocks removes the blocks starting with the regex matches, which must end with "{".
+func removeGradleBlocks(content string, regex *regexp.Regexp) string {
+	for {
+		match := regex.FindStringIndex(content)
+		if match == nil {
+			return content
+		}
+		block := getBalancedContent(content[match[1]-1:], '{', '}')
+		end := min(match[1]+len(block)+1, len(content))
+		content = content[:match[0]] + content[end:]
+	}
+}
+
+func newGradlePlugin(id string, version string) plugin {
+	return plugin{GroupId: id, ArtifactId: id + ".gradle.plugin", Version: version}
+}
@@ -0,0 +1,157 @@
+package internal
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseGradleMainResourceDirectories(t *testing.T) {
+	tests := []struct {
+		name                   string
+		content                string
+		expectedDirectories    []string
+		expectedReplaceDefault bool
+	}{
+		{
+			name:                   "no source sets",
+			content:                "plugins {\n    id 'java'\n}\n",
+			expectedDirectories:    nil,
+			expectedReplaceDefault: false,
+		},
+		{
+			name: "groovy srcDirs assignment",
+			content: `
+sourceSets {
+    main {
+        resources {
+            srcDirs = [
+                'src/main/resources',
+                'src/main/config'
+            ]
+        }
+    }
+}`,
+			expectedDirectories:    []string{"src/main/resources", "src/main/config"},
+			expectedReplaceDefault: true,
+		},
+		{
+			name: "groovy srcDir method call",
+			content: `
+sourceSets {
+    main {
+        resources {
+            srcDir 'src/main/config'
+        }
+    }
+    test {
+        resources {
+            srcDirs = ['src/test/config']
+        }
+    }
+}`,
+			expectedDirectories:    []string{"src/main/config"},
+			expectedReplaceDefault: false,
+		},
+		{
+			name:                   "groovy dotted assignment",
+			content:                "sourceSets.main.resources.srcDirs = ['src/main/config']\n",
+			expectedDirectories:    []string{"src/main/config"},
+			expectedReplaceDefault: true,
+		},
+		{
+			name:                   "kotlin indexed source set",
+			content:                `sourceSets["main"].resources.srcDir("src/main/config")`,
+			expectedDirectories:    []string{"src/main/config"},
+			expectedReplaceDefault: false,
+		},
+		{
+			name: "kotlin named source set",
+			content: `
+sourceSets {
+    named("main") {
+        resources.setSrcDirs(listOf("src/main/config", "src/main/extra"))
+    }
+}`,
+			expectedDirectories:    []string{"src/main/config", "src/main/extra"},
+			expectedReplaceDefault: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			directories, replaceDefault := parseGradleMainResourceDirectories(removeGradleComments(tt.content))
+			require.Equal(t, tt.expectedDirectories, directories)
+			require.Equal(t, tt.expectedReplaceDefault, replaceDefault)
+		})
+	}
+}
+
+func TestGetGradleResourceDirectories(t *testing.T) {
+	projectPath := filepath.Join("testdata", "java-spring", "project-six")
+	require.Equal(t, []string{
+		filepath.Join(projectPath, "src", "main", "resources"),
+		filepath.Join(projectPath, "src", "main", "config"),
+	}, GetGradleResourceDirectories(projectPath))
+	require.Nil(t, GetGradleResourceDirectories(filepath.Join("testdata", "java-spring", "project-one")))
+}
+
+func TestReadGradleBuildModel(t *testing.T) {
+	pom, ok := ReadGradleBuildModel(filepath.Join("testdata", "java-spring", "project-eleven"))
+	require.True(t, ok)
+	require.Equal(t, "project-eleven", pom.ArtifactId)
+	require.Equal(t, []plugin{
+		{GroupId: "java", ArtifactId: "java.gradle.plugin"},
+		{GroupId: "io.quarkus", ArtifactId: "io.quarkus.gradle.plugin", Version: "3.8.1"},
+	}, pom.Build.Plugins)
+	require.Equal(t, []dependency{
+		{GroupId: "io.quarkus.platform", ArtifactId: "quarkus-bom", Version: "3.8.1"},
+	}, pom.DependencyManagement.Dependencies)
+	require.Equal(t, []dependency{
+		{GroupId: "io.quarkus", ArtifactId: "quarkus-rest"},
+		{GroupId: "io.quarkus", ArtifactId: "quarkus-jdbc-postgresql"},
+		{GroupId: "io.quarkus", ArtifactId: "quarkus-redis-client"},
+		{GroupId: "io.quarkus", ArtifactId: "quarkus-junit5", Scope: "test"},
+	}, pom.Dependencies)
+
+	_, ok = ReadGradleBuildModel(filepath.Join("testdata", "java-spring", "project-one"))
+	require.False(t, ok)
+}
+
+func TestReadGradleBuildModelOfRootProject(t *testing.T) {
+	projectPath := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(projectPath, "build.gradle.kts"), []byte(`
+plugins {
+    java
+    id("org.springframework.boot") version "3.3.0" apply false
+    id("io.spring.dependency-management") version "1.1.5" apply(false)
+    id("com.diffplug.spotless") version "6.25.0"
+}
+
+allprojects {
+    repositories {
+        mavenCentral()
+    }
+}
+
+subprojects {
+    apply(plugin = "org.springframework.boot")
+    dependencies {
+        implementation("org.springframework.boot:spring-boot-starter-web")
+    }
+}
+
+dependencies {
+    testImplementation("org.junit.jupiter:junit-jupiter")
+}
+`), 0600))
+	pom, ok := ReadGradleBuildModel(projectPath)
+	require.True(t, ok)
+	require.Equal(t, []plugin{
+		{GroupId: "com.diffplug.spotless", ArtifactId: "com.diffplug.spotless.gradle.plugin", Version: "6.25.0"},
+	}, pom.Build.Plugins)
+	require.Equal(t, []dependency{
+		{GroupId: "org.junit.jupiter", ArtifactId: "junit-jupiter", Scope: "test"},
+	}, pom.Dependencies)
+}
@@ -0,0 +1,355 @@
+package internal
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// JavaAnnotation is an annotation found in Java or Kotlin source files, like
+// `@KafkaListener(topics = {"orders", "${app.topic}"})`.
+type JavaAnnotation struct {
+	// Name is the simple name, like "KafkaListener" for "@org.springframework.kafka.annotation.KafkaListener".
+	Name string
+	// Attributes maps attribute names to their string literal values. The attribute of a single unnamed value is
+	// "value". Values which are not string literals, like constants, are not recorded.
+	Attributes map[string][]string
+	FilePath   string
+	Line       int
+}
+
+// javaSourceDirectories are the source directories scanned for annotations, relative to the project path.
+var javaSourceDirectories = []string{
+	filepath.Join("src", "main", "java"),
+	filepath.Join("src", "main", "kotlin"),
+}
+
+var javaSourceFileExtensions = []string{".java", ".kt"}
+
+// ScanJavaAnnotations gets the annotations in the .java and .kt files of src/main/java and src/main/kotlin. Source
+// files are tokenized, not compiled, so the values of attributes are only recorded if they are string literals.
+func ScanJavaAnnotations(projectPath string) ([]JavaAnnotation, error) {
+	var result []JavaAnnotation
+	for _, directory := range javaSourceDirectories {
+		directory = filepath.Join(projectPath, directory)
+		err := filepath.WalkDir(directory, func(path string, entry fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			if entry.IsDir() || !isJavaSourceFile(path) {
+				return nil
+			}
+			content, err := os.ReadFile(path)
+			if err != nil {
+				return err
+			}
+			result = append(result, parseJavaAnnotations(path, string(content))...)
+			return nil
+		})
+		if errors.Is(err, fs.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("scanning source directory %s: %w", directory, err)
+		}
+	}
+	return result, nil
+}
+
+func isJavaSourceFile(path string) bool {
+	extension := strings.ToLower(filepath.Ext(path))
+	for _, sourceFileExtension := range javaSourceFileExtensions {
+		if extension == sourceFileExtension {
+			return true
+		}
+	}
+	return false
+}
+
+// HasJavaAnnotation checks whether any annotation has the name.
+func HasJavaAnnotation(annotations []JavaAnnotation, name string) bool {
+	for _, annotation := range annotations {
+		if annotation.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// GetJavaAnnotationAttributeValues gets the values of the attributes of all annotations with the name, in the order
+// they appear.
+func GetJavaAnnotationAttributeValues(annotations []JavaAnnotation, name string, attributeNames ...string) []string {
+	var result []string
+	for _, annotation := range annotations {
+		if annotation.Name != name {
+			continue
+		}
+		for _, attributeName := range attributeNames {
+			result = append(result, annotation.Attributes[attributeName]...)
+		}
+	}
+	return result
+}
+
+var placeholderRegex = regexp.MustCompile(`\$\{([^:}]+)(?::([^}]*))?}`)
+
+// ResolvePlaceholders resolves placeholders like "${app.queue}" or "${app.queue:orders}" in annotation attribute values
+// by the properties, then by environment variables, then by the default value. False is returned if any placeholder
+// can not be resolved, or if the value is a SpEL expression like "#{...}".
+func ResolvePlaceholders(value string, properties PropertyValues) (string, bool) {
+	if strings.Contains(value, "#{") {
+		return "", false
+	}
+	resolved := true
+	result := placeholderRegex.ReplaceAllStringFunc(value, func(placeholder string) string {
+		match := placeholderRegex.FindStringSubmatch(placeholder)
+		name := strings.TrimSpace(match[1])
+		if propertyValue, ok := GetPropertyValue(properties, name); ok {
+			return propertyValue
+		}
+		if environmentVariableValue, ok := os.LookupEnv(name); ok {
+			return environmentVariableValue
+		}
+		if strings.Contains(placeholder, ":") {
+			return match[2]
+		}
+		resolved = false
+		return placeholder
+	})
+	return result, resolved
+}
+
+// GetUnresolvedPlaceholderNames gets the names of the placeholders like "${db-password}" in the value, which are not
+// resolved by the properties and don't have default values. Environment variables are not used, because they are from
+// the machine running the analysis instead of the deployed application.
+func GetUnresolvedPlaceholderNames(value string, properties PropertyValues) []string {
+	var result []string
+	for _, match := range placeholderRegex.FindAllStringSubmatch(value, -1) {
+		name := strings.TrimSpace(match[1])
+		if strings.Contains(match[0], ":") {
+			continue
+		}
+		if _, ok := GetPropertyValue(properties, name); !ok {
+			result = AppendAndDistinctInOrder(result, name)
+		}
+	}
+	return result
+}
+
+type javaTokenKind int
+
+const (
+	javaTokenIdentifier javaTokenKind = iota
+	javaTokenString
+	javaTokenSymbol
+)
+
+type javaToken struct {
+	kind javaTokenKind
+	text string
+	line int
+}
+
+func (t javaToken) isSymbol(symbol string) bool {
+	return t.kind == javaTokenSymbol && t.text == symbol
+}
+
+// parseJavaAnnotations gets the annotations in the content of a Java or Kotlin source file. Annotations used as
+// attribute values, like @TopicPartition in @KafkaListener, are also returned.
+func parseJavaAnnotations(filePath string, content string) []JavaAnnotation {
+	tokens := tokenizeJavaSource(content)
+	var result []JavaAnnotation
+	for i := 0; i+1 < len(tokens); i++ {
+		if !tokens[i].isSymbol("@") || tokens[i+1].kind != javaTokenIdentifier || tokens[i+1].text == "interface" {
+			continue
+		}
+		// Qualified names like @org.springframework.jms.annotation.JmsListener and Kotlin use-site targets like
+		// @field:Value, the last identifier is the simple name.
+		end := i + 1
+		for end+2 < len(tokens) && (tokens[end+1].isSymbol(".") || tokens[end+1].isSymbol(":")) &&
+			tokens[end+2].kind == javaTokenIdentifier {
+			end += 2
+		}
+		annotation := JavaAnnotation{
+			Name:       tokens[end].text,
+			Attributes: make(map[string][]string),
+			FilePath:   filePath,
+			Line:       tokens[i].line,
+		}
+		if end+1 < len(tokens) && tokens[end+1].isSymbol("(") {
+			closing := findClosingJavaToken(tokens, end+1)
+			parseJavaAnnotationAttributes(tokens[end+2:closing], annotation.Attributes)
+		}
+		result = append(result, annotation)
+	}
+	return result
+}
+
+// findClosingJavaToken finds the index of the token closing the bracket at the start index, or len(tokens) if not
+// found.
+func findClosingJavaToken(tokens []javaToken, start int) int {
+	depth := 0
+	for i := start; i < len(tokens); i++ {
+		if tokens[i].kind != javaTokenSymbol {
+			continue
+		}
+		switch tokens[i].text {
+		case "(", "{", "[":
+			depth++
+		case ")", "}", "]":
+			depth--
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+	return len(tokens)
+}
+
+// parseJavaAnnotationAttributes parses attributes like `"orders"`, `topics = {"a", "b"}` (Java),
+// `topics = ["a", "b"]` or `topics = arrayOf("a", "b")` (Kotlin).
+func parseJavaAnnotationAttributes(tokens []javaToken, attributes map[string][]string) {
+	depth := 0
+	start := 0
+	for i := 0; i <= len(tokens); i++ {
+		if i < len(tokens) {
+			if tokens[i].kind == javaTokenSymbol {
+				switch tokens[i].text {
+				case "(", "{", "[":
+					depth++
+				case ")", "}", "]":
+					depth--
+				}
+			}
+			if depth != 0 || !tokens[i].isSymbol(",") {
+				continue
+			}
+		}
+		attribute := tokens[start:i]
+		start = i + 1
+		if len(attribute) == 0 {
+			continue
+		}
+		name := "value"
+		if len(attribute) >= 2 && attribute[0].kind == javaTokenIdentifier && attribute[1].isSymbol("=") {
+			name = attribute[0].text
+			attribute = attribute[2:]
+		}
+		attributes[name] = append(attributes[name], getJavaStringValues(attribute)...)
+	}
+}
+
+// getJavaStringValues gets the string literals in the tokens, concatenated literals like `"a" + "b"` are joined.
+func getJavaStringValues(tokens []javaToken) []string {
+	var result []string
+	for i, token := range tokens {
+		if token.kind != javaTokenString {
+			continue
+		}
+		if i >= 2 && tokens[i-1].isSymbol("+") && tokens[i-2].kind == javaTokenString {
+			result[len(result)-1] += token.text
+		} else {
+			result = append(result, token.text)
+		}
+	}
+	return result
+}
+
+// tokenizeJavaSource splits Java or Kotlin source code into identifiers, string literals and symbols. Comments,
+// whitespace, number literals and character literals are dropped.
+func tokenizeJavaSource(content string) []javaToken {
+	var tokens []javaToken
+	line := 1
+	for i := 0; i < len(content); {
+		c := content[i]
+		switch {
+		case c == '\n':
+			line++
+			i++
+		case c == ' ' || c == '\t' || c == '\r' || c == '\f':
+			i++
+		case strings.HasPrefix(content[i:], "//"):
+			for i < len(content) && content[i] != '\n' {
+				i++
+			}
+		case strings.HasPrefix(content[i:], "/*"):
+			end := strings.Index(content[i+2:], "*/")
+			if end == -1 {
+				end = len(content) - i - 2
+			}
+			line += strings.Count(content[i:i+2+end], "\n")
+			i += end + 4
+		case strings.HasPrefix(content[i:], `"""`):
+			// Java text blocks and Kotlin raw strings.
+			end := strings.Index(content[i+3:], `"""`)
+			if end == -1 {
+				end = len(content) - i - 3
+			}
+			text := content[i+3 : i+3+end]
+			tokens = append(tokens, javaToken{javaTokenString, strings.TrimSpace(text), line})
+			line += strings.Count(text, "\n")
+			i += end + 6
+		case c == '"':
+			text, length := readJavaStringLiteral(content[i:])
+			tokens = append(tokens, javaToken{javaTokenString, text, line})
+			i += length
+		case c == '\'':
+			_, length := readJavaStringLiteral(content[i:])
+			i += length
+		case isJavaIdentifierStart(c):
+			start := i
+			for i < len(content) && (isJavaIdentifierStart(content[i]) || isDigit(content[i])) {
+				i++
+			}
+			tokens = append(tokens, javaToken{javaTokenIdentifier, content[start:i], line})
+		case isDigit(c):
+			for i < len(content) && (isJavaIdentifierStart(content[i]) || isDigit(content[i]) || content[i] == '.') {
+				i++
+			}
+		default:
+			tokens = append(tokens, javaToken{javaTokenSymbol, string(c), line})
+			i++
+		}
+	}
+	return tokens
+}
+
+// readJavaStringLiteral reads the string or character literal at the start of the content, which starts with its
+// quote. The unescaped value and the length of the literal are returned. Unclosed literals end at the line end.
+func readJavaStringLiteral(content string) (string, int) {
+	quote := content[0]
+	var builder strings.Builder
+	i := 1
+	for i < len(content) && content[i] != quote && content[i] != '\n' {
+		if content[i] == '\\' && i+1 < len(content) {
+			i++
+			switch content[i] {
+			case 'n':
+				builder.WriteByte('\n')
+			case 't':
+				builder.WriteByte('\t')
+			default:
+				builder.WriteByte(content[i])
+			}
+		} else {
+			builder.WriteByte(content[i])
+		}
+		i++
+	}
+	if i < len(content) && content[i] == quote {
+		i++
+	}
+	return builder.String(), i
+}
+
+func isJavaIdentifierStart(c byte) bool {
+	return c == '_' || c == '$' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || c >= 0x80
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
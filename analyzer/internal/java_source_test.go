@@ -0,0 +1,130 @@
+package internal
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestScanJavaAnnotations(t *testing.T) {
+	projectPath := filepath.Join("testdata", "java-spring", "project-nine")
+	annotations, err := ScanJavaAnnotations(projectPath)
+	require.NoError(t, err)
+
+	require.True(t, HasJavaAnnotation(annotations, "SpringBootApplication"))
+	require.False(t, HasJavaAnnotation(annotations, "RestController"))
+	require.Equal(t, []string{"${app.queue:orders-queue}", "order-events"},
+		GetJavaAnnotationAttributeValues(annotations, "JmsListener", "destination"))
+	require.Equal(t, []string{"audit"}, GetJavaAnnotationAttributeValues(annotations, "JmsListener", "subscription"))
+	require.Equal(t, []string{"payments", "refunds"},
+		GetJavaAnnotationAttributeValues(annotations, "KafkaListener", "topics"))
+
+	kotlinFilePath := filepath.Join(projectPath, "src", "main", "kotlin", "com", "example", "orders",
+		"PaymentListener.kt")
+	for _, annotation := range annotations {
+		if annotation.Name == "KafkaListener" {
+			require.Equal(t, kotlinFilePath, annotation.FilePath)
+			require.Equal(t, 12, annotation.Line)
+		}
+	}
+}
+
+func TestScanJavaAnnotationsWithoutSourceDirectory(t *testing.T) {
+	annotations, err := ScanJavaAnnotations(filepath.Join("testdata", "java-spring", "project-one"))
+	require.NoError(t, err)
+	require.Empty(t, annotations)
+}
+
+func TestParseJavaAnnotations(t *testing.T) {
+	tests := []struct {
+		name     string
+		content  string
+		expected []JavaAnnotation
+	}{
+		{
+			name:    "unnamed value",
+			content: `@GetMapping("/orders") String list();`,
+			expected: []JavaAnnotation{
+				{Name: "GetMapping", Attributes: map[string][]string{"value": {"/orders"}}, Line: 1},
+			},
+		},
+		{
+			name:    "qualified name and array",
+			content: "@org.springframework.kafka.annotation.KafkaListener(\n  topics = {\"a\", \"b\"}, id = \"x\")",
+			expected: []JavaAnnotation{
+				{Name: "KafkaListener", Attributes: map[string][]string{"topics": {"a", "b"}, "id": {"x"}}, Line: 1},
+			},
+		},
+		{
+			name:    "Kotlin arrayOf and use-site target",
+			content: `@field:KafkaListener(topics = arrayOf("a"))`,
+			expected: []JavaAnnotation{
+				{Name: "KafkaListener", Attributes: map[string][]string{"topics": {"a"}}, Line: 1},
+			},
+		},
+		{
+			name:    "nested annotation",
+			content: `@KafkaListener(topicPartitions = @TopicPartition(topic = "a"))`,
+			expected: []JavaAnnotation{
+				{Name: "KafkaListener", Attributes: map[string][]string{"topicPartitions": {"a"}}, Line: 1},
+				{Name: "TopicPartition", Attributes: map[string][]string{"topic": {"a"}}, Line: 1},
+			},
+		},
+		{
+			name:    "strings, comments and annotation declarations",
+			content: "String s = \"@NotAnnotation\"; // @Comment\n/* @Comment */ public @interface Custom {}\n@Service",
+			expected: []JavaAnnotation{
+				{Name: "Service", Attributes: map[string][]string{}, Line: 3},
+			},
+		},
+		{
+			name:    "escaped quote and constant",
+			content: `@JmsListener(destination = "a\"b", containerFactory = FACTORY)`,
+			expected: []JavaAnnotation{
+				{Name: "JmsListener", Attributes: map[string][]string{"destination": {`a"b`}, "containerFactory": nil},
+					Line: 1},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.expected, parseJavaAnnotations("", tt.content))
+		})
+	}
+}
+
+func TestResolvePlaceholders(t *testing.T) {
+	t.Setenv("ORDERS_QUEUE", "queue-from-env")
+	properties := NewTestPropertyValues(map[string]string{"app.topic": "orders"})
+	tests := []struct {
+		value            string
+		expected         string
+		expectedResolved bool
+	}{
+		{"orders", "orders", true},
+		{"${app.topic}", "orders", true},
+		{"${APP_TOPIC}", "orders", true},
+		{"prefix-${app.topic}", "prefix-orders", true},
+		{"${ORDERS_QUEUE}", "queue-from-env", true},
+		{"${app.missing:default}", "default", true},
+		{"${app.missing}", "", false},
+		{"#{'${app.topic}'.split(',')}", "", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			resolved, ok := ResolvePlaceholders(tt.value, properties)
+			require.Equal(t, tt.expectedResolved, ok)
+			if ok {
+				require.Equal(t, tt.expected, resolved)
+			}
+		})
+	}
+}
+
+func TestGetUnresolvedPlaceholderNames(t *testing.T) {
+	properties := NewTestPropertyValues(map[string]string{"app.user": "orders"})
+	require.Equal(t, []string{"db-password"},
+		GetUnresolvedPlaceholderNames("${app.user}:${db-password}:${db-port:5432}:${db-password}", properties))
+	require.Nil(t, GetUnresolvedPlaceholderNames("${APP_USER}", properties))
+}
@@ -0,0 +1,159 @@
+package internal
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// javaVersionPomPropertyNames are the pom properties declaring the Java release, from high precedence to low
+// precedence. java.version is used by spring-boot-starter-parent to set the compiler release.
+var javaVersionPomPropertyNames = []string{
+	"maven.compiler.release",
+	"maven.compiler.source",
+	"maven.compiler.target",
+	"java.version",
+}
+
+// GetJavaVersion gets the Java release used by the project, like "17". The sources are, from high precedence to low
+// precedence:
+//  1. Pom properties: maven.compiler.release, maven.compiler.source, maven.compiler.target, then java.version.
+//  2. Gradle toolchain like "JavaLanguageVersion.of(17)", then sourceCompatibility.
+//  3. .java-version used by jenv, then .sdkmanrc used by SDKMAN!, in the project directory then in the directories
+//     above it until projectRootPath.
+//
+// Versions like "1.8" and "17.0.2" are normalized to "8" and "17". Empty is returned if the version is not declared.
+func GetJavaVersion(projectPath string, projectRootPath string, pom Pom) string {
+	for _, name := range javaVersionPomPropertyNames {
+		if version := normalizeJavaVersion(getPomProperty(pom, name)); version != "" {
+			return version
+		}
+	}
+	if content, ok := readGradleBuildFile(projectPath); ok {
+		if version := getGradleJavaVersion(content); version != "" {
+			return version
+		}
+	}
+	for directory := projectPath; ; directory = filepath.Dir(directory) {
+		if version := readJavaVersionFile(directory); version != "" {
+			return version
+		}
+		relativePath, err := filepath.Rel(projectRootPath, directory)
+		if err != nil || relativePath == "." || strings.HasPrefix(relativePath, "..") ||
+			filepath.Dir(directory) == directory {
+			return ""
+		}
+	}
+}
+
+func getPomProperty(pom Pom, name string) string {
+	for _, property := range pom.Properties.Entries {
+		if property.XMLName.Local == name {
+			return strings.TrimSpace(property.Value)
+		}
+	}
+	return ""
+}
+
+var javaVersionRegex = regexp.MustCompile(`^(?:1\.)?(\d+)`)
+
+// normalizeJavaVersion normalizes versions like "1.8", "17.0.2", "17.0.2-tem" or "VERSION_17" to the release number.
+// Empty is returned for values like "${java.version}" which are not versions.
+func normalizeJavaVersion(version string) string {
+	version = strings.TrimPrefix(strings.Trim(strings.TrimSpace(version), `"'`), "VERSION_")
+	version = strings.ReplaceAll(version, "_", ".")
+	match := javaVersionRegex.FindStringSubmatch(version)
+	if match == nil {
+		return ""
+	}
+	return match[1]
+}
+
+var gradleToolchainJavaVersionRegex = regexp.MustCompile(`JavaLanguageVersion\.of\(\s*["']?([\d.]+)["']?\s*\)`)
+var gradleSourceCompatibilityRegex = regexp.MustCompile(
+	`\bsourceCompatibility\s*=\s*(?:JavaVersion\.)?["']?([\w.]+)["']?`)
+
+func getGradleJavaVersion(content string) string {
+	for _, regex := range []*regexp.Regexp{gradleToolchainJavaVersionRegex, gradleSourceCompatibilityRegex} {
+		if match := regex.FindStringSubmatch(content); match != nil {
+			if version := normalizeJavaVersion(match[1]); version != "" {
+				return version
+			}
+		}
+	}
+	return ""
+}
+
+// readJavaVersionFile reads .java-version like "17.0.2", or .sdkmanrc like "java=17.0.2-tem".
+func readJavaVersionFile(directory string) string {
+	if content, err := os.ReadFile(filepath.Join(directory, ".java-version")); err == nil {
+		if version := normalizeJavaVersion(string(content)); version != "" {
+			return version
+		}
+	}
+	file, err := os.Open(filepath.Join(directory, ".sdkmanrc"))
+	if err != nil {
+		return ""
+	}
+	defer file.Close()
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		parts := strings.SplitN(scanner.Text(), "=", 2)
+		if len(parts) == 2 && strings.TrimSpace(parts[0]) == "java" {
+			return normalizeJavaVersion(parts[1])
+		}
+	}
+	return ""
+}
+
+var gradleSpringBootPluginVersionRegex = regexp.MustCompile(
+	`id\s*\(?\s*["']org\.springframework\.boot["']\s*\)?\s*version\s*\(?\s*["']([^"']+)["']`)
+
+// GetSpringBootVersion gets the Spring Boot version used by the project, like "3.3.0". The sources are, from high
+// precedence to low precedence:
+//  1. The parent spring-boot-starter-parent or spring-boot-dependencies.
+//  2. Spring Boot artifacts in dependencyManagement, like the imported spring-boot-dependencies BOM.
+//  3. The version of spring-boot-maven-plugin.
+//  4. The version of the org.springframework.boot Gradle plugin.
+//
+// Empty is returned if the version is not found.
+func GetSpringBootVersion(projectPath string, pom Pom) string {
+	if pom.Parent.GroupId == "org.springframework.boot" &&
+		(pom.Parent.ArtifactId == "spring-boot-starter-parent" || pom.Parent.ArtifactId == "spring-boot-dependencies") &&
+		strings.TrimSpace(pom.Parent.Version) != "" {
+		return strings.TrimSpace(pom.Parent.Version)
+	}
+	for _, dependency := range pom.DependencyManagement.Dependencies {
+		if dependency.GroupId == "org.springframework.boot" &&
+			(dependency.ArtifactId == "spring-boot-dependencies" || dependency.ArtifactId == "spring-boot") &&
+			isResolvedVersion(dependency.Version) {
+			return strings.TrimSpace(dependency.Version)
+		}
+	}
+	for _, plugin := range pom.Build.Plugins {
+		if plugin.GroupId == "org.springframework.boot" && plugin.ArtifactId == "spring-boot-maven-plugin" &&
+			isResolvedVersion(plugin.Version) {
+			return strings.TrimSpace(plugin.Version)
+		}
+	}
+	if content, ok := readGradleBuildFile(projectPath); ok {
+		if match := gradleSpringBootPluginVersionRegex.FindStringSubmatch(content); match != nil {
+			return match[1]
+		}
+	}
+	return ""
+}
+
+// isResolvedVersion checks whether the version is set and is not a property reference like "${spring-boot.version}".
+func isResolvedVersion(version string) bool {
+	version = strings.TrimSpace(version)
+	return version != "" && !strings.Contains(version, "${")
+}
+
+// GetMajorVersion gets the major version, like "3" for "3.3.0". Empty is returned if the version is empty.
+func GetMajorVersion(version string) string {
+	major, _, _ := strings.Cut(strings.TrimSpace(version), ".")
+	return major
+}
@@ -0,0 +1,128 @@
+package internal
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetJavaVersion(t *testing.T) {
+	pom, err := ParseTestPom(`
+		<project>
+			<properties>
+				<java.version>11</java.version>
+				<maven.compiler.release>${java.version}</maven.compiler.release>
+				<maven.compiler.source>1.8</maven.compiler.source>
+			</properties>
+		</project>`)
+	require.NoError(t, err)
+	projectRootPath := filepath.Join("testdata", "java-spring", "project-ten")
+	projectPath := filepath.Join(projectRootPath, "service")
+	require.Equal(t, "8", GetJavaVersion(projectPath, projectRootPath, pom))
+	require.Equal(t, "17", GetJavaVersion(projectPath, projectRootPath, Pom{}))
+	require.Equal(t, "21", GetJavaVersion(projectRootPath, projectRootPath, Pom{}))
+	require.Equal(t, "", GetJavaVersion(filepath.Join("testdata", "java-spring", "project-one"),
+		filepath.Join("testdata", "java-spring", "project-one"), Pom{}))
+
+	directory := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(directory, ".java-version"), []byte("17.0.2\n"), 0600))
+	require.Equal(t, "17", GetJavaVersion(directory, directory, Pom{}))
+}
+
+func TestNormalizeJavaVersion(t *testing.T) {
+	tests := []struct {
+		version  string
+		expected string
+	}{
+		{"17", "17"},
+		{"1.8", "8"},
+		{"17.0.2", "17"},
+		{"21.0.2-tem", "21"},
+		{"VERSION_1_8", "8"},
+		{"VERSION_17", "17"},
+		{"'11'", "11"},
+		{"${java.version}", ""},
+		{"", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.version, func(t *testing.T) {
+			require.Equal(t, tt.expected, normalizeJavaVersion(tt.version))
+		})
+	}
+}
+
+func TestGetGradleJavaVersion(t *testing.T) {
+	require.Equal(t, "17", getGradleJavaVersion("java { toolchain { languageVersion = JavaLanguageVersion.of(17) } }"))
+	require.Equal(t, "11", getGradleJavaVersion("sourceCompatibility = JavaVersion.VERSION_11"))
+	require.Equal(t, "8", getGradleJavaVersion("sourceCompatibility = '1.8'"))
+	require.Equal(t, "", getGradleJavaVersion("dependencies {}"))
+}
+
+func TestGetSpringBootVersion(t *testing.T) {
+	tests := []struct {
+		name     string
+		pom      string
+		expected string
+	}{
+		{
+			name: "parent",
+			pom: `
+				<project>
+					<parent>
+						<groupId>org.springframework.boot</groupId>
+						<artifactId>spring-boot-starter-parent</artifactId>
+						<version>3.3.0</version>
+					</parent>
+				</project>`,
+			expected: "3.3.0",
+		},
+		{
+			name: "bom",
+			pom: `
+				<project>
+					<dependencyManagement>
+						<dependencies>
+							<dependency>
+								<groupId>org.springframework.boot</groupId>
+								<artifactId>spring-boot-dependencies</artifactId>
+								<version>2.7.18</version>
+							</dependency>
+						</dependencies>
+					</dependencyManagement>
+				</project>`,
+			expected: "2.7.18",
+		},
+		{
+			name: "plugin",
+			pom: `
+				<project>
+					<build>
+						<plugins>
+							<plugin>
+								<groupId>org.springframework.boot</groupId>
+								<artifactId>spring-boot-maven-plugin</artifactId>
+								<version>3.1.2</version>
+							</plugin>
+						</plugins>
+					</build>
+				</project>`,
+			expected: "3.1.2",
+		},
+		{
+			name:     "not found",
+			pom:      `<project></project>`,
+			expected: "",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pom, err := ParseTestPom(tt.pom)
+			require.NoError(t, err)
+			require.Equal(t, tt.expected, GetSpringBootVersion("", pom))
+		})
+	}
+	projectPath := filepath.Join("testdata", "java-spring", "project-ten", "service")
+	require.Equal(t, "3.2.5", GetSpringBootVersion(projectPath, Pom{}))
+}
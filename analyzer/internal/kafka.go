@@ -0,0 +1,86 @@
+package internal
+
+import (
+	"net"
+	"sort"
+	"strings"
+)
+
+// kafkaBootstrapServersPropertyNames are the properties of Spring Kafka, Spring Cloud Stream Kafka binder, Quarkus and
+// raw kafka-clients configuration declaring the bootstrap servers.
+var kafkaBootstrapServersPropertyNames = []string{
+	"spring.kafka.bootstrap-servers",
+	"spring.kafka.consumer.bootstrap-servers",
+	"spring.kafka.producer.bootstrap-servers",
+	"spring.kafka.admin.bootstrap-servers",
+	"spring.kafka.streams.bootstrap-servers",
+	"spring.kafka.properties.bootstrap.servers",
+	"spring.cloud.stream.kafka.binder.brokers",
+	"kafka.bootstrap.servers",
+	"bootstrap.servers",
+}
+
+// GetKafkaBootstrapServers gets the Kafka bootstrap servers like "my-namespace.servicebus.windows.net:9093", in the
+// order they are declared.
+func GetKafkaBootstrapServers(properties PropertyValues) []string {
+	var result []string
+	for _, name := range kafkaBootstrapServersPropertyNames {
+		for _, server := range GetPropertyValueList(properties, name) {
+			if server = strings.TrimSpace(server); server != "" {
+				result = AppendAndDistinctInOrder(result, server)
+			}
+		}
+	}
+	return result
+}
+
+// IsEventHubsKafkaServer checks whether the Kafka server is the Kafka endpoint of an Event Hubs namespace, like
+// "my-namespace.servicebus.windows.net:9093".
+func IsEventHubsKafkaServer(server string) bool {
+	return strings.HasSuffix(strings.ToLower(getKafkaServerHost(server)), ".servicebus.windows.net")
+}
+
+// IsExternalKafkaServer checks whether the Kafka server is a Kafka cluster outside Azure, like
+// "pkc-4r087.westeurope.azure.confluent.cloud:9092". Servers used in development, like "localhost:9092" and
+// "kafka:9092" in Docker Compose, are not external.
+func IsExternalKafkaServer(server string) bool {
+	host := getKafkaServerHost(server)
+	return strings.Contains(host, ".") && net.ParseIP(host) == nil && !IsEventHubsKafkaServer(server)
+}
+
+func getKafkaServerHost(server string) string {
+	server = strings.TrimSpace(server)
+	if index := strings.Index(server, "://"); index != -1 {
+		server = server[index+3:]
+	}
+	if host, _, err := net.SplitHostPort(server); err == nil {
+		return host
+	}
+	return server
+}
+
+// GetKafkaTopics gets the topics in spring.kafka.template.default-topic, and in the "topic" or "topics" settings under
+// spring.kafka.consumer and spring.kafka.listener, like "spring.kafka.consumer.properties.topics". The result is
+// sorted.
+func GetKafkaTopics(properties PropertyValues) []string {
+	var result []string
+	defaultTopicName := CanonicalPropertyName("spring.kafka.template.default-topic")
+	prefixes := []string{"spring.kafka.consumer.", "spring.kafka.listener."}
+	for canonicalName, value := range properties {
+		isTopic := canonicalName == defaultTopicName
+		if hasAnyPrefix(canonicalName, prefixes) {
+			lastSegment := canonicalName[strings.LastIndex(canonicalName, ".")+1:]
+			isTopic = lastSegment == "topic" || lastSegment == "topics"
+		}
+		if !isTopic {
+			continue
+		}
+		for _, topic := range strings.Split(value.Value, ",") {
+			if topic = strings.TrimSpace(topic); topic != "" {
+				result = AppendAndDistinctInOrder(result, topic)
+			}
+		}
+	}
+	sort.Strings(result)
+	return result
+}
@@ -0,0 +1,50 @@
+package internal
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetKafkaBootstrapServers(t *testing.T) {
+	properties := NewTestPropertyValues(map[string]string{
+		"spring.kafka.bootstrap-servers":           "my-namespace.servicebus.windows.net:9093",
+		"SPRING_CLOUD_STREAM_KAFKA_BINDER_BROKERS": "localhost:9092, my-namespace.servicebus.windows.net:9093",
+	})
+	require.Equal(t, []string{"my-namespace.servicebus.windows.net:9093", "localhost:9092"},
+		GetKafkaBootstrapServers(properties))
+	require.Nil(t, GetKafkaBootstrapServers(nil))
+}
+
+func TestKafkaServerKinds(t *testing.T) {
+	tests := []struct {
+		server            string
+		expectedEventHubs bool
+		expectedExternal  bool
+	}{
+		{"my-namespace.servicebus.windows.net:9093", true, false},
+		{"SASL_SSL://My-Namespace.servicebus.windows.net:9093", true, false},
+		{"pkc-4r087.westeurope.azure.confluent.cloud:9092", false, true},
+		{"localhost:9092", false, false},
+		{"kafka:29092", false, false},
+		{"127.0.0.1:9092", false, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.server, func(t *testing.T) {
+			require.Equal(t, tt.expectedEventHubs, IsEventHubsKafkaServer(tt.server))
+			require.Equal(t, tt.expectedExternal, IsExternalKafkaServer(tt.server))
+		})
+	}
+}
+
+func TestGetKafkaTopics(t *testing.T) {
+	properties := NewTestPropertyValues(map[string]string{
+		"spring.kafka.template.default-topic":     "orders",
+		"spring.kafka.consumer.properties.topics": "payments, orders",
+		"spring.kafka.listener.topic":             "shipments",
+		"spring.kafka.consumer.group-id":          "order-service",
+		"spring.kafka.producer.properties.topic":  "ignored",
+		"app.kafka.topic":                         "ignored",
+	})
+	require.Equal(t, []string{"orders", "payments", "shipments"}, GetKafkaTopics(properties))
+}
@@ -0,0 +1,47 @@
+package internal
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+const keyVaultPropertySourcesName = "spring.cloud.azure.keyvault.secret.property-sources"
+
+// GetKeyVaultPropertySourceEndpoints gets the endpoints of spring.cloud.azure.keyvault.secret.property-sources[*], in
+// the order of the property sources.
+func GetKeyVaultPropertySourceEndpoints(properties PropertyValues) []string {
+	var result []string
+	for i := 0; ; i++ {
+		endpoint, ok := GetPropertyValue(properties, fmt.Sprintf("%s[%d].endpoint", keyVaultPropertySourcesName, i))
+		if !ok {
+			return result
+		}
+		if endpoint = strings.TrimSpace(endpoint); endpoint != "" {
+			result = AppendAndDistinctInOrder(result, endpoint)
+		}
+	}
+}
+
+// GetKeyVaultSecretProperties gets the property names which are expected to come from the Key Vault property sources,
+// sorted by name. They are the secret-keys of the property sources, and the placeholders like "${db-password}" in the
+// property values which can not be resolved by other properties and don't have default values. Nil is returned if no
+// Key Vault property source is configured.
+func GetKeyVaultSecretProperties(properties PropertyValues) []string {
+	if len(GetKeyVaultPropertySourceEndpoints(properties)) == 0 {
+		return nil
+	}
+	var result []string
+	for i := 0; ; i++ {
+		prefix := fmt.Sprintf("%s[%d].", keyVaultPropertySourcesName, i)
+		if _, ok := GetPropertyValue(properties, prefix+"endpoint"); !ok {
+			break
+		}
+		result = AppendAndDistinctInOrder(result, GetPropertyValueList(properties, prefix+"secret-keys")...)
+	}
+	for _, value := range properties {
+		result = AppendAndDistinctInOrder(result, GetUnresolvedPlaceholderNames(value.Value, properties)...)
+	}
+	sort.Strings(result)
+	return result
+}
@@ -0,0 +1,25 @@
+package internal
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetKeyVaultSecretProperties(t *testing.T) {
+	properties := NewTestPropertyValues(map[string]string{
+		"spring.cloud.azure.keyvault.secret.property-sources[0].endpoint":    "https://orders.vault.azure.net/",
+		"spring.cloud.azure.keyvault.secret.property-sources[0].secret-keys": "spring-datasource-password",
+		"spring.cloud.azure.keyvault.secret.property-sources[1].endpoint":    "https://shared.vault.azure.net/",
+		"spring.cloud.azure.keyvault.secret.property-sources[1].secret-keys": "api-key, mail-password",
+		"spring.datasource.username":                                         "${db-user}",
+		"spring.datasource.url":                                              "jdbc:postgresql://${db-host:localhost}/orders",
+	})
+	require.Equal(t, []string{"https://orders.vault.azure.net/", "https://shared.vault.azure.net/"},
+		GetKeyVaultPropertySourceEndpoints(properties))
+	require.Equal(t, []string{"api-key", "db-user", "mail-password", "spring-datasource-password"},
+		GetKeyVaultSecretProperties(properties))
+	require.Nil(t, GetKeyVaultSecretProperties(NewTestPropertyValues(map[string]string{
+		"spring.datasource.username": "${db-user}",
+	})))
+}
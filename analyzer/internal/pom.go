@@ -71,7 +71,16 @@ type dependencyManagement struct {
 
 // Build represents the build configuration which can contain plugins.
 type build struct {
-	Plugins []plugin `xml:"plugins>plugin"`
+	Plugins   []plugin   `xml:"plugins>plugin"`
+	Resources []resource `xml:"resources>resource"`
+}
+
+// Resource represents a resource directory, which is copied to the classpath root.
+type resource struct {
+	Directory string   `xml:"directory"`
+	Filtering string   `xml:"filtering"`
+	Includes  []string `xml:"includes>include"`
+	Excludes  []string `xml:"excludes>exclude"`
 }
 
 // Plugin represents a build plugin.
@@ -0,0 +1,103 @@
+package internal
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// PropertyOrigin is where a property value is defined.
+type PropertyOrigin struct {
+	// FilePath is the file defining the property. For values not read from a file, like ConfigMap entries, it also
+	// describes the entry.
+	FilePath string
+	// Line and Column start from 1, and are 0 if unknown.
+	Line   int
+	Column int
+	// DocumentIndex is the index of the document in multi-document files, starts from 0.
+	DocumentIndex int
+	// Profile is the profile of the profile-specific file or of the document activated by
+	// spring.config.activate.on-profile. Empty if it's not profile-specific.
+	Profile string
+}
+
+func (o PropertyOrigin) String() string {
+	result := o.FilePath
+	if o.Line > 0 {
+		result = fmt.Sprintf("%s:%d", result, o.Line)
+		if o.Column > 0 {
+			result = fmt.Sprintf("%s:%d", result, o.Column)
+		}
+	}
+	var details []string
+	if o.DocumentIndex > 0 {
+		details = append(details, fmt.Sprintf("document %d", o.DocumentIndex))
+	}
+	if o.Profile != "" {
+		details = append(details, "profile "+o.Profile)
+	}
+	if len(details) > 0 {
+		result = fmt.Sprintf("%s (%s)", result, strings.Join(details, ", "))
+	}
+	return result
+}
+
+// PropertyValue is a property value and where it's defined.
+type PropertyValue struct {
+	// Name is the property name as it's defined, like "spring.datasource.url" or "SPRING_DATASOURCE_URL".
+	Name   string
+	Value  string
+	Origin PropertyOrigin
+	// Overrides are the values of the same property overridden by this value, ordered from high precedence to low
+	// precedence.
+	Overrides []PropertyValue
+}
+
+// PropertyValues maps canonical property names, see CanonicalPropertyName, to values. Names referring to the same
+// property under relaxed binding are kept only once, and the name as it's defined is in PropertyValue.Name.
+type PropertyValues map[string]PropertyValue
+
+// ToMap returns the names as they are defined and the values, without origins.
+func (p PropertyValues) ToMap() map[string]string {
+	result := make(map[string]string, len(p))
+	for _, value := range p {
+		result[value.Name] = value.Value
+	}
+	return result
+}
+
+// Get gets the value of the property whose name matches the given name under relaxed binding.
+func (p PropertyValues) Get(name string) (PropertyValue, bool) {
+	value, ok := p[CanonicalPropertyName(name)]
+	return value, ok
+}
+
+// Set sets the property value. The value of the same property under relaxed binding is replaced and recorded in
+// Overrides of the new value.
+func (p PropertyValues) Set(name string, value PropertyValue) {
+	canonicalName := CanonicalPropertyName(name)
+	value.Name = name
+	if existing, ok := p[canonicalName]; ok {
+		overridden := existing
+		overridden.Overrides = nil
+		value.Overrides = append(append([]PropertyValue{overridden}, existing.Overrides...), value.Overrides...)
+	}
+	p[canonicalName] = value
+}
+
+// Merge sets the values of other in the order of their names, so they take precedence over the existing values.
+func (p PropertyValues) Merge(other PropertyValues) {
+	for _, canonicalName := range other.sortedCanonicalNames() {
+		value := other[canonicalName]
+		p.Set(value.Name, value)
+	}
+}
+
+func (p PropertyValues) sortedCanonicalNames() []string {
+	result := make([]string, 0, len(p))
+	for canonicalName := range p {
+		result = append(result, canonicalName)
+	}
+	sort.Strings(result)
+	return result
+}
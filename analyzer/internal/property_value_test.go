@@ -0,0 +1,43 @@
+package internal
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPropertyValuesSet(t *testing.T) {
+	values := make(PropertyValues)
+	first := PropertyValue{Value: "first", Origin: PropertyOrigin{FilePath: "application.yml", Line: 3, Column: 7}}
+	second := PropertyValue{Value: "second", Origin: PropertyOrigin{FilePath: "application.properties", Line: 1}}
+	third := PropertyValue{Value: "third", Origin: PropertyOrigin{FilePath: ".env", Line: 2}}
+	values.Set("spring.datasource.url", first)
+	values.Set("spring.datasource.URL", second)
+	values.Set("SPRING_DATASOURCE_URL", third)
+
+	require.Len(t, values, 1)
+	value, ok := values.Get("spring.datasource.url")
+	require.True(t, ok)
+	require.Equal(t, "SPRING_DATASOURCE_URL", value.Name)
+	require.Equal(t, "third", value.Value)
+	first.Name, second.Name = "spring.datasource.url", "spring.datasource.URL"
+	require.Equal(t, []PropertyValue{second, first}, value.Overrides)
+	require.Equal(t, map[string]string{"SPRING_DATASOURCE_URL": "third"}, values.ToMap())
+}
+
+func TestPropertyOriginString(t *testing.T) {
+	tests := []struct {
+		origin   PropertyOrigin
+		expected string
+	}{
+		{PropertyOrigin{FilePath: "application.properties"}, "application.properties"},
+		{PropertyOrigin{FilePath: "application.properties", Line: 2}, "application.properties:2"},
+		{PropertyOrigin{FilePath: "application.yml", Line: 2, Column: 5, DocumentIndex: 1, Profile: "dev"},
+			"application.yml:2:5 (document 1, profile dev)"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.expected, func(t *testing.T) {
+			require.Equal(t, tt.expected, tt.origin.String())
+		})
+	}
+}
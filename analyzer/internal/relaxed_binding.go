@@ -0,0 +1,116 @@
+package internal
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// CanonicalPropertyName converts a property name written in any of Spring Boot's relaxed binding forms into a single
+// canonical form, so that names referring to the same property can be compared.
+//
+// Examples:
+//   - spring.cloud.azure.eventhubs.event-hub-name -> spring.cloud.azure.eventhubs.eventhubname
+//   - spring.cloud.azure.eventhubs.eventHubName -> spring.cloud.azure.eventhubs.eventhubname
+//   - SPRING_CLOUD_AZURE_EVENTHUBS_EVENTHUBNAME -> spring.cloud.azure.eventhubs.eventhubname
+//   - my.list[0].first_name, MY_LIST_0_FIRSTNAME -> my.list[0].firstname
+func CanonicalPropertyName(name string) string {
+	name = strings.TrimSpace(name)
+	if isEnvironmentVariableStyleName(name) {
+		return canonicalNameFromEnvironmentVariableStyleName(name)
+	}
+	var builder strings.Builder
+	inIndex := false
+	for _, c := range name {
+		switch {
+		case inIndex:
+			// Keep indices and map keys like [0] or [key.with.dots] as they are.
+			builder.WriteRune(c)
+			if c == ']' {
+				inIndex = false
+			}
+		case c == '[':
+			inIndex = true
+			builder.WriteRune(c)
+		case c == '-' || c == '_':
+			// Dashes and underscores are only word separators in relaxed binding.
+		default:
+			builder.WriteRune(unicode.ToLower(c))
+		}
+	}
+	return builder.String()
+}
+
+// isEnvironmentVariableStyleName checks whether the name looks like SPRING_DATASOURCE_URL.
+func isEnvironmentVariableStyleName(name string) bool {
+	if name == "" || strings.ContainsAny(name, ".[]-") {
+		return false
+	}
+	for _, c := range name {
+		if unicode.IsLower(c) {
+			return false
+		}
+	}
+	return true
+}
+
+// canonicalNameFromEnvironmentVariableStyleName converts SPRING_DATASOURCE_URL to spring.datasource.url, and a
+// numeric element like MY_LIST_0_NAME to a list index: my.list[0].name.
+func canonicalNameFromEnvironmentVariableStyleName(name string) string {
+	var builder strings.Builder
+	for _, element := range strings.Split(strings.ToLower(name), "_") {
+		if element == "" {
+			continue
+		}
+		if isNumeric(element) && builder.Len() > 0 {
+			builder.WriteString("[" + element + "]")
+			continue
+		}
+		if builder.Len() > 0 {
+			builder.WriteString(".")
+		}
+		builder.WriteString(element)
+	}
+	return builder.String()
+}
+
+func isNumeric(s string) bool {
+	for _, c := range s {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+	return s != ""
+}
+
+// GetPropertyValue gets the value of the property whose name matches the given name under relaxed binding.
+func GetPropertyValue(properties PropertyValues, name string) (string, bool) {
+	value, ok := properties.Get(name)
+	return value.Value, ok
+}
+
+// GetPropertyValueList gets the values of a list property, which can be a comma-separated value like "a,b", or
+// indexed entries like name[0] and name[1].
+func GetPropertyValueList(properties PropertyValues, name string) []string {
+	var result []string
+	if value, ok := GetPropertyValue(properties, name); ok {
+		result = appendCommaSeparatedValues(result, value)
+	}
+	for i := 0; ; i++ {
+		value, ok := GetPropertyValue(properties, fmt.Sprintf("%s[%d]", name, i))
+		if !ok {
+			break
+		}
+		result = appendCommaSeparatedValues(result, value)
+	}
+	return result
+}
+
+func appendCommaSeparatedValues(values []string, commaSeparatedValue string) []string {
+	for _, value := range strings.Split(commaSeparatedValue, ",") {
+		if value = strings.TrimSpace(value); value != "" {
+			values = append(values, value)
+		}
+	}
+	return values
+}
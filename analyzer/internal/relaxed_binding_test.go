@@ -0,0 +1,78 @@
+package internal
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCanonicalPropertyName(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{"KebabCase", "spring.cloud.azure.eventhubs.event-hub-name", "spring.cloud.azure.eventhubs.eventhubname"},
+		{"CamelCase", "spring.cloud.azure.eventhubs.eventHubName", "spring.cloud.azure.eventhubs.eventhubname"},
+		{"Underscore", "spring.cloud.azure.eventhubs.event_hub_name", "spring.cloud.azure.eventhubs.eventhubname"},
+		{"EnvironmentVariable", "SPRING_CLOUD_AZURE_EVENTHUBS_EVENTHUBNAME", "spring.cloud.azure.eventhubs.eventhubname"},
+		{"SingleWordEnvironmentVariable", "PORT", "port"},
+		{"Index", "my.list[0].first-name", "my.list[0].firstname"},
+		{"EnvironmentVariableIndex", "MY_LIST_0_FIRSTNAME", "my.list[0].firstname"},
+		{"EnvironmentVariableIndexAtEnd", "MY_LIST_1", "my.list[1]"},
+		{"MapKeyWithDots", "my.map[Key.With.Dots]", "my.map[Key.With.Dots]"},
+		{"Spaces", "  spring.datasource.url ", "spring.datasource.url"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.expected, CanonicalPropertyName(tt.input))
+		})
+	}
+}
+
+func TestGetPropertyValue(t *testing.T) {
+	properties := NewTestPropertyValues(map[string]string{
+		"spring.cloud.azure.eventhubs.eventHubName": "hub-one",
+		"SPRING_DATASOURCE_URL":                     "jdbc:postgresql://localhost:5432/db-one",
+		"my.list[0].name":                           "zero",
+	})
+	value, ok := GetPropertyValue(properties, "spring.cloud.azure.eventhubs.event-hub-name")
+	require.True(t, ok)
+	require.Equal(t, "hub-one", value)
+
+	value, ok = GetPropertyValue(properties, "spring.datasource.url")
+	require.True(t, ok)
+	require.Equal(t, "jdbc:postgresql://localhost:5432/db-one", value)
+
+	value, ok = GetPropertyValue(properties, "MY_LIST_0_NAME")
+	require.True(t, ok)
+	require.Equal(t, "zero", value)
+
+	_, ok = GetPropertyValue(properties, "not.exist")
+	require.False(t, ok)
+}
+
+func TestGetBindingDestinationMapWithRelaxedNames(t *testing.T) {
+	properties := NewTestPropertyValues(map[string]string{
+		"spring.cloud.stream.bindings.consume-in-0.destination":  "queue-one",
+		"SPRING_CLOUD_STREAM_BINDINGS_SUPPLYOUT0_DESTINATION":    "queue-two",
+		"spring.cloud.stream.bindings.consume-in-0.group":        "group-one",
+		"spring.cloud.stream.bindings.consume-in-0.contentType":  "application/json",
+		"spring.cloud.stream.bindings.consume-in-0.Destination2": "not-a-destination",
+	})
+	require.ElementsMatch(t, []string{"queue-one", "queue-two"}, GetDistinctBindingDestinationValues(properties))
+}
+
+func TestGetStreamBindings(t *testing.T) {
+	properties := NewTestPropertyValues(map[string]string{
+		"spring.cloud.stream.bindings.consume-in-0.destination": "orders",
+		"spring.cloud.stream.bindings.consume-in-0.group":       "billing",
+		"SPRING_CLOUD_STREAM_BINDINGS_SUPPLYOUT0_DESTINATION":   "payments",
+		"spring.cloud.stream.bindings.audit-in-0.group":         "without-destination",
+		"spring.cloud.stream.bindings.empty-in-0.destination":   " ",
+	})
+	require.Equal(t, []StreamBinding{
+		{Name: "consumein0", Destination: "orders", Group: "billing"},
+		{Name: "supplyout0", Destination: "payments"},
+	}, GetStreamBindings(properties))
+}
@@ -0,0 +1,154 @@
+package internal
+
+import (
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// ResourceRoot is a directory whose files are copied to the classpath root when building the project.
+type ResourceRoot struct {
+	Directory string
+	// Filtering means @...@ placeholders in the files are replaced by build properties, like Maven resource
+	// filtering with the "@" delimiter configured by spring-boot-starter-parent.
+	Filtering bool
+	// Includes and Excludes are Ant-style patterns like "**/application*.yml", relative to Directory.
+	Includes []string
+	Excludes []string
+}
+
+// GetResourceRoots gets the resource roots from the build model. The resources declared in the pom are used, and the
+// resource directories of the main source set in build.gradle or build.gradle.kts are added if the file exists.
+// Default to src/main/resources if none of them is declared.
+func GetResourceRoots(projectPath string, pom Pom) []ResourceRoot {
+	var result []ResourceRoot
+	for _, resource := range pom.Build.Resources {
+		directory := strings.TrimSpace(resource.Directory)
+		if directory == "" {
+			continue
+		}
+		if !filepath.IsAbs(directory) && !isWindowsAbsolutePath(directory) {
+			directory = filepath.Join(projectPath, directory)
+		}
+		result = append(result, ResourceRoot{
+			Directory: directory,
+			Filtering: strings.TrimSpace(resource.Filtering) == "true",
+			Includes:  resource.Includes,
+			Excludes:  resource.Excludes,
+		})
+	}
+	for _, directory := range GetGradleResourceDirectories(projectPath) {
+		result = append(result, ResourceRoot{Directory: directory})
+	}
+	if len(result) == 0 {
+		result = append(result, ResourceRoot{Directory: filepath.Join(projectPath, "src", "main", "resources")})
+	}
+	return result
+}
+
+var windowsAbsolutePathRegex = regexp.MustCompile(`^[A-Za-z]:[\\/]`)
+
+func isWindowsAbsolutePath(path string) bool {
+	return windowsAbsolutePathRegex.MatchString(path)
+}
+
+// getResourceRootDirectories gets the distinct directories of the resource roots, a directory can be declared
+// multiple times with different includes and excludes.
+func getResourceRootDirectories(resourceRoots []ResourceRoot) []string {
+	var result []string
+	for _, root := range resourceRoots {
+		result = AppendAndDistinctInOrder(result, root.Directory)
+	}
+	return result
+}
+
+// matchResourceRoots checks whether the file is inside the directory of any resource root, whether it's included by
+// any resource root, and whether it's included by any resource root which enables filtering.
+func matchResourceRoots(resourceRoots []ResourceRoot, filePath string) (insideRoot bool, included bool, filtered bool) {
+	for _, root := range resourceRoots {
+		relativePath, err := filepath.Rel(root.Directory, filePath)
+		if err != nil || relativePath == ".." || strings.HasPrefix(relativePath, ".."+string(filepath.Separator)) {
+			continue
+		}
+		insideRoot = true
+		relativePath = filepath.ToSlash(relativePath)
+		if len(root.Includes) > 0 && !matchAnyAntPattern(root.Includes, relativePath) {
+			continue
+		}
+		if matchAnyAntPattern(root.Excludes, relativePath) {
+			continue
+		}
+		included = true
+		filtered = filtered || root.Filtering
+	}
+	return insideRoot, included, filtered
+}
+
+func matchAnyAntPattern(patterns []string, path string) bool {
+	for _, pattern := range patterns {
+		if matchAntPattern(strings.TrimSpace(pattern), path) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchAntPattern matches Ant-style patterns used by Maven includes and excludes: "**" matches zero or more
+// directories, "*" matches zero or more characters in a file name, "?" matches one character.
+func matchAntPattern(pattern string, path string) bool {
+	var builder strings.Builder
+	builder.WriteString("^")
+	pattern = filepath.ToSlash(pattern)
+	for i := 0; i < len(pattern); i++ {
+		switch {
+		case strings.HasPrefix(pattern[i:], "**/"):
+			builder.WriteString("(?:.*/)?")
+			i += 2
+		case strings.HasPrefix(pattern[i:], "**"):
+			builder.WriteString(".*")
+			i++
+		case pattern[i] == '*':
+			builder.WriteString("[^/]*")
+		case pattern[i] == '?':
+			builder.WriteString("[^/]")
+		default:
+			builder.WriteString(regexp.QuoteMeta(string(pattern[i])))
+		}
+	}
+	builder.WriteString("$")
+	matched, err := regexp.MatchString(builder.String(), path)
+	return err == nil && matched
+}
+
+// GetResourceFilteringProperties gets the build properties used to replace @...@ placeholders in filtered resources.
+func GetResourceFilteringProperties(pom Pom) map[string]string {
+	result := make(map[string]string)
+	for _, property := range pom.Properties.Entries {
+		result[property.XMLName.Local] = strings.TrimSpace(property.Value)
+	}
+	putIfNotEmpty(result, "project.groupId", pom.GroupId)
+	putIfNotEmpty(result, "project.artifactId", pom.ArtifactId)
+	putIfNotEmpty(result, "project.version", pom.Version)
+	putIfNotEmpty(result, "project.parent.groupId", pom.Parent.GroupId)
+	putIfNotEmpty(result, "project.parent.artifactId", pom.Parent.ArtifactId)
+	putIfNotEmpty(result, "project.parent.version", pom.Parent.Version)
+	return result
+}
+
+func putIfNotEmpty(properties map[string]string, key string, value string) {
+	if value = strings.TrimSpace(value); value != "" {
+		properties[key] = value
+	}
+}
+
+var resourceFilteringPlaceholderRegex = regexp.MustCompile(`@([A-Za-z0-9_.\-]+)@`)
+
+// applyResourceFiltering replaces @...@ placeholders by the filtering properties, unknown placeholders are kept.
+func applyResourceFiltering(value string, filteringProperties map[string]string) string {
+	return resourceFilteringPlaceholderRegex.ReplaceAllStringFunc(value, func(placeholder string) string {
+		if replacement, ok := filteringProperties[strings.Trim(placeholder, "@")]; ok {
+			return replacement
+		}
+		return placeholder
+	})
+}
@@ -0,0 +1,87 @@
+package internal
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestMatchAntPattern(t *testing.T) {
+	tests := []struct {
+		pattern  string
+		path     string
+		expected bool
+	}{
+		{"**/application*.yml", "application.yml", true},
+		{"**/application*.yml", "config/application-dev.yml", true},
+		{"**/application*.yml", "application.properties", false},
+		{"*.properties", "config/application.properties", false},
+		{"config/**", "config/a/b.yml", true},
+		{"application?.yml", "application1.yml", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.pattern+" "+tt.path, func(t *testing.T) {
+			require.Equal(t, tt.expected, matchAntPattern(tt.pattern, tt.path))
+		})
+	}
+}
+
+func TestGetResourceRoots(t *testing.T) {
+	projectPath := filepath.Join("testdata", "java-spring", "project-one")
+	require.Equal(t, []ResourceRoot{{Directory: filepath.Join(projectPath, "src", "main", "resources")}},
+		GetResourceRoots(projectPath, Pom{}))
+
+	pom := Pom{Build: build{Resources: []resource{
+		{Directory: "/absolute/resources", Filtering: "true", Includes: []string{"**/application*.yml"}},
+		{Directory: "src/main/config"},
+	}}}
+	require.Equal(t, []ResourceRoot{
+		{Directory: "/absolute/resources", Filtering: true, Includes: []string{"**/application*.yml"}},
+		{Directory: filepath.Join(projectPath, "src", "main", "config")},
+	}, GetResourceRoots(projectPath, pom))
+}
+
+func TestReadSpringBootConfigWithResourceRoots(t *testing.T) {
+	projectPath := filepath.Join("testdata", "java-spring", "project-six")
+	resourcesDirectory := filepath.Join(projectPath, "src", "main", "resources")
+	// Same as the resources configured by spring-boot-starter-parent.
+	resourceRoots := []ResourceRoot{
+		{
+			Directory: resourcesDirectory,
+			Filtering: true,
+			Includes:  []string{"**/application*.yml", "**/application*.yaml", "**/application*.properties"},
+		},
+		{
+			Directory: resourcesDirectory,
+			Excludes:  []string{"**/application*.yml", "**/application*.yaml", "**/application*.properties"},
+		},
+	}
+	resourceRoots = append(resourceRoots, ResourceRoot{
+		Directory: filepath.Join(projectPath, "src", "main", "config"),
+		Filtering: true,
+	})
+	pom := Pom{GroupId: "com.example", ArtifactId: "project-six", Version: "1.0.0"}
+	config, err := ReadSpringBootConfig(projectPath, SpringBootConfigOptions{
+		ResourceRoots:       resourceRoots,
+		FilteringProperties: GetResourceFilteringProperties(pom),
+	})
+	require.NoError(t, err)
+	properties := config.PropertyValues.ToMap()
+	require.Equal(t, "config", properties["app.source"])
+	require.Equal(t, "1.0.0", properties["app.version"])
+	require.Equal(t, "project-six", properties["app.name"])
+	require.Equal(t, "@not.exist@", properties["app.unknown"])
+}
+
+func TestReadSpringBootConfigWithIncludedResourcesOnly(t *testing.T) {
+	projectPath := filepath.Join("testdata", "java-spring", "project-six")
+	config, err := ReadSpringBootConfig(projectPath, SpringBootConfigOptions{
+		ResourceRoots: []ResourceRoot{{
+			Directory: filepath.Join(projectPath, "src", "main", "resources"),
+			Excludes:  []string{"**/*.yml"},
+		}},
+	})
+	require.NoError(t, err)
+	require.Equal(t, "", config.PropertyValues.ToMap()["app.source"])
+}
@@ -0,0 +1,63 @@
+package internal
+
+import (
+	"slices"
+	"strings"
+)
+
+const (
+	ServiceBusEntityTypeQueue = "queue"
+	ServiceBusEntityTypeTopic = "topic"
+)
+
+// GetServiceBusBindingEntityType gets the entity type of the Service Bus binder binding, from
+// spring.cloud.stream.servicebus.bindings.<name>.producer.entity-type, then from the consumer one. The result is
+// ServiceBusEntityTypeQueue, ServiceBusEntityTypeTopic, or empty if not set.
+func GetServiceBusBindingEntityType(properties PropertyValues, bindingName string) string {
+	return getServiceBusEntityType(properties,
+		"spring.cloud.stream.servicebus.bindings."+bindingName+".producer.entity-type",
+		"spring.cloud.stream.servicebus.bindings."+bindingName+".consumer.entity-type")
+}
+
+func getServiceBusEntityType(properties PropertyValues, names ...string) string {
+	switch strings.ToLower(GetFirstPropertyValue(properties, names...)) {
+	case ServiceBusEntityTypeQueue:
+		return ServiceBusEntityTypeQueue
+	case ServiceBusEntityTypeTopic:
+		return ServiceBusEntityTypeTopic
+	default:
+		return ""
+	}
+}
+
+// ServiceBusEntity is a queue or a topic used by a Service Bus client.
+type ServiceBusEntity struct {
+	Name string
+	// EntityType is ServiceBusEntityTypeQueue, ServiceBusEntityTypeTopic, or empty if not set.
+	EntityType string
+	// Subscription is the subscription of the topic used by consumers. Empty if not set.
+	Subscription string
+}
+
+// GetServiceBusClientEntities gets the entities of the clients configured by spring-cloud-azure-starter-servicebus.
+// The producer, consumer and processor use spring.cloud.azure.servicebus.<client>.entity-name and entity-type, which
+// default to spring.cloud.azure.servicebus.entity-name and entity-type. The consumer and processor use
+// subscription-name for topics.
+func GetServiceBusClientEntities(properties PropertyValues) []ServiceBusEntity {
+	prefix := "spring.cloud.azure.servicebus."
+	var result []ServiceBusEntity
+	for _, client := range []string{"producer.", "consumer.", "processor."} {
+		entity := ServiceBusEntity{
+			Name: GetFirstPropertyValue(properties, prefix+client+"entity-name", prefix+"entity-name"),
+			EntityType: getServiceBusEntityType(properties, prefix+client+"entity-type",
+				prefix+"entity-type"),
+		}
+		if client != "producer." {
+			entity.Subscription = GetFirstPropertyValue(properties, prefix+client+"subscription-name")
+		}
+		if entity.Name != "" && !slices.Contains(result, entity) {
+			result = append(result, entity)
+		}
+	}
+	return result
+}
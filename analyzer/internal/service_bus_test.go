@@ -0,0 +1,35 @@
+package internal
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetServiceBusBindingEntityType(t *testing.T) {
+	properties := NewTestPropertyValues(map[string]string{
+		"spring.cloud.stream.servicebus.bindings.supply-out-0.producer.entity-type": "TOPIC",
+		"SPRING_CLOUD_STREAM_SERVICEBUS_BINDINGS_CONSUMEIN0_CONSUMER_ENTITYTYPE":    "queue",
+		"spring.cloud.stream.servicebus.bindings.audit-in-0.consumer.entity-type":   "unknown",
+	})
+	require.Equal(t, ServiceBusEntityTypeTopic, GetServiceBusBindingEntityType(properties, "supplyout0"))
+	require.Equal(t, ServiceBusEntityTypeQueue, GetServiceBusBindingEntityType(properties, "consumein0"))
+	require.Equal(t, "", GetServiceBusBindingEntityType(properties, "auditin0"))
+	require.Equal(t, "", GetServiceBusBindingEntityType(properties, "missing"))
+}
+
+func TestGetServiceBusClientEntities(t *testing.T) {
+	properties := NewTestPropertyValues(map[string]string{
+		"spring.cloud.azure.servicebus.entity-name":                 "orders",
+		"spring.cloud.azure.servicebus.entity-type":                 "topic",
+		"spring.cloud.azure.servicebus.processor.subscription-name": "billing",
+		"spring.cloud.azure.servicebus.consumer.entity-name":        "tasks",
+		"spring.cloud.azure.servicebus.consumer.entity-type":        "queue",
+	})
+	require.Equal(t, []ServiceBusEntity{
+		{Name: "orders", EntityType: ServiceBusEntityTypeTopic},
+		{Name: "tasks", EntityType: ServiceBusEntityTypeQueue},
+		{Name: "orders", EntityType: ServiceBusEntityTypeTopic, Subscription: "billing"},
+	}, GetServiceBusClientEntities(properties))
+	require.Nil(t, GetServiceBusClientEntities(nil))
+}
@@ -0,0 +1,411 @@
+package internal
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// SpringBootConfig is the config data of a Spring Boot project.
+type SpringBootConfig struct {
+	// PropertyValues are the resolved property values with their origins and the values they override.
+	PropertyValues PropertyValues
+	// ExternalConfigImports are imported locations which can not be read locally,
+	// like "configserver:http://localhost:8888" or "vault://".
+	ExternalConfigImports []string
+	// SkippedFiles are the malformed config files skipped when SkipMalformedFiles is enabled.
+	SkippedFiles []*ConfigFileError
+	// SkippedTemplates are the Kubernetes manifests skipped because they are Helm templates, which are only valid
+	// manifests after being rendered.
+	SkippedTemplates []string
+}
+
+// configFileExtensions is ordered from low precedence to high precedence. Same as Spring Boot, ".properties" takes
+// precedence over YAML if both exist in the same location.
+var configFileExtensions = []string{".yaml", ".yml", ".properties"}
+
+// SpringBootConfigOptions are the options used to read SpringBootConfig.
+type SpringBootConfigOptions struct {
+	// ResourceRoots are the directories copied to the classpath root. Default to src/main/resources.
+	ResourceRoots []ResourceRoot
+	// FilteringProperties are used to replace @...@ placeholders in files of filtered resource roots.
+	FilteringProperties map[string]string
+	// ConfigRepositoryPath is the local directory or git checkout used by Spring Cloud Config Server. If set, the
+	// properties served by the config server are read and take precedence over the local config data.
+	ConfigRepositoryPath string
+	// DeploymentConfigSearchPaths are the directories to search .env files and Kubernetes manifests in. The config
+	// used when deploying the application is read as the lowest precedence property source, see loadDeploymentConfig.
+	DeploymentConfigSearchPaths []string
+	// SkipMalformedFiles skips the config files which can not be read or parsed and records them in
+	// SpringBootConfig.SkippedFiles, instead of returning an error.
+	SkipMalformedFiles bool
+	// Profiles are activated before the profiles in spring.profiles.active. They are used by frameworks which
+	// activate profiles by other means, like QUARKUS_PROFILE of Quarkus or MICRONAUT_ENVIRONMENTS of Micronaut.
+	Profiles []string
+}
+
+// ReadSpringBootConfig reads the config data by following Spring Boot's config data loading order, from low
+// precedence to high precedence:
+//  1. The config used when deploying the application if DeploymentConfigSearchPaths is set, see
+//     loadDeploymentConfig.
+//  2. bootstrap.{yaml,yml,properties} used by Spring Cloud, then its profile-specific variants.
+//  3. application.{yaml,yml,properties} in classpath:/ and classpath:/config/, then its profile-specific variants.
+//  4. application.{yaml,yml,properties} in file:./, file:./config/ and file:./config/*/, then its profile-specific
+//     variants.
+//  5. Locations in spring.config.additional-location, then its profile-specific variants.
+//  6. Files in the Spring Cloud Config Server repository if ConfigRepositoryPath is set, see loadConfigRepository.
+//
+// Files imported by spring.config.import take precedence over the file that imports them. The default locations
+// can be replaced by spring.config.location. Same as Spring Boot, spring.config.location and
+// spring.config.additional-location are read from environment variables.
+func ReadSpringBootConfig(projectPath string, options SpringBootConfigOptions) (SpringBootConfig, error) {
+	resourceRoots := options.ResourceRoots
+	if len(resourceRoots) == 0 {
+		resourceRoots = []ResourceRoot{{Directory: filepath.Join(projectPath, "src", "main", "resources")}}
+	}
+	loader := configDataLoader{
+		projectPath:                 projectPath,
+		resourceRoots:               resourceRoots,
+		classpathRoots:              getResourceRootDirectories(resourceRoots),
+		filteringProperties:         options.FilteringProperties,
+		configRepositoryPath:        options.ConfigRepositoryPath,
+		deploymentConfigSearchPaths: options.DeploymentConfigSearchPaths,
+		skipMalformedFiles:          options.SkipMalformedFiles,
+		profiles:                    options.Profiles,
+		loadedFiles:                 make(map[string]bool),
+	}
+	return loader.load()
+}
+
+type configDataLoader struct {
+	projectPath                 string
+	resourceRoots               []ResourceRoot
+	classpathRoots              []string
+	filteringProperties         map[string]string
+	configRepositoryPath        string
+	deploymentConfigSearchPaths []string
+	skipMalformedFiles          bool
+	profiles                    []string
+	loadedFiles                 map[string]bool
+	externalImports             []string
+	skippedFiles                []*ConfigFileError
+	skippedTemplates            []string
+}
+
+// configDataLocation is a directory to search config files in, or a config file.
+type configDataLocation struct {
+	path        string
+	isDirectory bool
+}
+
+func (l *configDataLoader) load() (SpringBootConfig, error) {
+	locationGroups := l.getLocationGroups()
+	var bootstrapLocations []configDataLocation
+	for _, root := range l.classpathRoots {
+		bootstrapLocations = append(bootstrapLocations, configDataLocation{root, true})
+	}
+	bootstrapDocuments, err := l.loadLocations(bootstrapLocations, "bootstrap", "")
+	if err != nil {
+		return SpringBootConfig{}, err
+	}
+	applicationDocuments := make([][]propertyDocument, len(locationGroups))
+	for i, group := range locationGroups {
+		if applicationDocuments[i], err = l.loadLocations(group, "application", ""); err != nil {
+			return SpringBootConfig{}, err
+		}
+	}
+	var nonProfileDocuments []propertyDocument
+	nonProfileDocuments = append(nonProfileDocuments, bootstrapDocuments...)
+	for _, documents := range applicationDocuments {
+		nonProfileDocuments = append(nonProfileDocuments, documents...)
+	}
+	var deploymentDocuments []propertyDocument
+	if len(l.deploymentConfigSearchPaths) > 0 {
+		applicationNames := []string{GetNameFromDirPath(l.projectPath)}
+		nonProfileValues := mergeDocuments(withoutActivatedDocuments(nonProfileDocuments), nil)
+		if name, ok := nonProfileValues.Get("spring.application.name"); ok {
+			applicationNames = AppendAndDistinctInOrder(applicationNames, name.Value)
+		}
+		if deploymentDocuments, err = l.loadDeploymentConfig(applicationNames); err != nil {
+			return SpringBootConfig{}, err
+		}
+		nonProfileDocuments = append(deploymentDocuments, nonProfileDocuments...)
+	}
+	// Same as Spring Boot, documents activated by spring.config.activate.on-profile can't activate profiles.
+	profiles := AppendAndDistinctInOrder(append([]string(nil), l.profiles...), GetPropertyValueList(
+		mergeDocuments(withoutActivatedDocuments(nonProfileDocuments), nil), "spring.profiles.active")...)
+
+	// Profile-specific files always take precedence over the non-specific ones in the same location group.
+	var documents []propertyDocument
+	documents = append(documents, deploymentDocuments...)
+	documents = append(documents, bootstrapDocuments...)
+	for _, profile := range profiles {
+		profileDocuments, err := l.loadLocations(bootstrapLocations, "bootstrap", profile)
+		if err != nil {
+			return SpringBootConfig{}, err
+		}
+		documents = append(documents, profileDocuments...)
+	}
+	for i, group := range locationGroups {
+		documents = append(documents, applicationDocuments[i]...)
+		for _, profile := range profiles {
+			profileDocuments, err := l.loadLocations(group, "application", profile)
+			if err != nil {
+				return SpringBootConfig{}, err
+			}
+			documents = append(documents, profileDocuments...)
+		}
+	}
+	if l.configRepositoryPath != "" {
+		repositoryDocuments, err := l.loadConfigRepository(mergeDocuments(documents, profiles), profiles)
+		if err != nil {
+			return SpringBootConfig{}, err
+		}
+		documents = append(documents, repositoryDocuments...)
+	}
+	propertyValues := mergeDocuments(documents, profiles)
+	return SpringBootConfig{
+		PropertyValues:        propertyValues,
+		ExternalConfigImports: l.externalImports,
+		SkippedFiles:          l.skippedFiles,
+		SkippedTemplates:      l.skippedTemplates,
+	}, nil
+}
+
+func (l *configDataLoader) getLocationGroups() [][]configDataLocation {
+	var groups [][]configDataLocation
+	if locations := getConfigLocationsFromEnvironment("SPRING_CONFIG_LOCATION"); len(locations) > 0 {
+		groups = append(groups, l.resolveLocations(locations))
+	} else {
+		var classpathGroup []configDataLocation
+		for _, root := range l.classpathRoots {
+			classpathGroup = append(classpathGroup,
+				configDataLocation{root, true},
+				configDataLocation{filepath.Join(root, "config"), true})
+		}
+		fileGroup := []configDataLocation{
+			{l.projectPath, true},
+			{filepath.Join(l.projectPath, "config"), true},
+		}
+		for _, dir := range getSubDirectories(filepath.Join(l.projectPath, "config")) {
+			fileGroup = append(fileGroup, configDataLocation{dir, true})
+		}
+		groups = append(groups, classpathGroup, fileGroup)
+	}
+	if locations := getConfigLocationsFromEnvironment("SPRING_CONFIG_ADDITIONALLOCATION"); len(locations) > 0 {
+		groups = append(groups, l.resolveLocations(locations))
+	}
+	return groups
+}
+
+func getConfigLocationsFromEnvironment(environmentVariableName string) []string {
+	var result []string
+	for _, location := range strings.Split(os.Getenv(environmentVariableName), ",") {
+		if location = strings.TrimSpace(location); location != "" {
+			result = append(result, location)
+		}
+	}
+	return result
+}
+
+func (l *configDataLoader) resolveLocations(locations []string) []configDataLocation {
+	var result []configDataLocation
+	for _, location := range locations {
+		result = append(result, l.resolveLocation(location, l.projectPath)...)
+	}
+	return result
+}
+
+var configDataLocationSchemeRegex = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9+.-]+:`)
+
+// resolveLocation resolves locations like "optional:classpath:azure.yml", "file:./config/" or "azure.yml". Relative
+// paths without prefix are resolved against baseDirectory. Locations which can not be read locally, like
+// "configserver:" or "vault://", are recorded as external imports.
+func (l *configDataLoader) resolveLocation(location string, baseDirectory string) []configDataLocation {
+	location = strings.TrimPrefix(strings.TrimSpace(location), "optional:")
+	isDirectory := strings.HasSuffix(location, "/")
+	var paths []string
+	switch {
+	case strings.HasPrefix(location, "classpath:") || strings.HasPrefix(location, "classpath*:"):
+		relativePath := strings.TrimLeft(location[strings.Index(location, ":")+1:], "/")
+		for _, root := range l.classpathRoots {
+			paths = append(paths, filepath.Join(root, relativePath))
+		}
+	case strings.HasPrefix(location, "file:"):
+		path := strings.TrimPrefix(location, "file:")
+		if !filepath.IsAbs(path) {
+			path = filepath.Join(l.projectPath, path)
+		}
+		paths = append(paths, path)
+	case configDataLocationSchemeRegex.MatchString(location):
+		l.externalImports = AppendAndDistinctInOrder(l.externalImports, location)
+		return nil
+	default:
+		path := location
+		if !filepath.IsAbs(path) {
+			path = filepath.Join(baseDirectory, path)
+		}
+		paths = append(paths, path)
+	}
+	var result []configDataLocation
+	for _, path := range paths {
+		result = append(result, configDataLocation{path, isDirectory})
+	}
+	return result
+}
+
+func (l *configDataLoader) loadLocations(locations []configDataLocation, name string,
+	profile string) ([]propertyDocument, error) {
+	var documents []propertyDocument
+	for _, location := range locations {
+		locationDocuments, err := l.loadLocation(location, name, profile)
+		if err != nil {
+			return nil, err
+		}
+		documents = append(documents, locationDocuments...)
+	}
+	return documents, nil
+}
+
+// loadLocation loads the config file(s) of the location, and the files imported by them. An empty profile means
+// the non-profile-specific files.
+func (l *configDataLoader) loadLocation(location configDataLocation, name string,
+	profile string) ([]propertyDocument, error) {
+	documents, err := l.loadLocationFiles(location, name, profile)
+	if err != nil {
+		return nil, err
+	}
+	if profile != "" {
+		for _, document := range documents {
+			for key, value := range document.values {
+				if value.Origin.Profile == "" {
+					value.Origin.Profile = profile
+					document.values[key] = value
+				}
+			}
+		}
+	}
+	return documents, nil
+}
+
+func (l *configDataLoader) loadLocationFiles(location configDataLocation, name string,
+	profile string) ([]propertyDocument, error) {
+	if !location.isDirectory {
+		path := location.path
+		if profile != "" {
+			extension := filepath.Ext(path)
+			path = strings.TrimSuffix(path, extension) + "-" + profile + extension
+		}
+		return l.loadFile(path)
+	}
+	var documents []propertyDocument
+	for _, extension := range configFileExtensions {
+		fileName := name + extension
+		if profile != "" {
+			fileName = name + "-" + profile + extension
+		}
+		fileDocuments, err := l.loadFile(filepath.Join(location.path, fileName))
+		if err != nil {
+			return nil, err
+		}
+		documents = append(documents, fileDocuments...)
+	}
+	return documents, nil
+}
+
+func (l *configDataLoader) loadFile(path string) ([]propertyDocument, error) {
+	if l.loadedFiles[path] || !fileExists(path) {
+		return nil, nil
+	}
+	l.loadedFiles[path] = true
+	insideRoot, included, filtered := matchResourceRoots(l.resourceRoots, path)
+	if insideRoot && !included {
+		// Not copied to the classpath because of the includes and excludes of the resource roots.
+		return nil, nil
+	}
+	var fileDocuments []propertyDocument
+	var err error
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".properties":
+		fileDocuments, err = readPropertiesInPropertiesFile(path)
+	case ".yaml", ".yml":
+		fileDocuments, err = readPropertiesInYamlFile(path)
+	default:
+		return nil, nil
+	}
+	if err != nil {
+		return nil, l.skipOrReturnError(err)
+	}
+	var documents []propertyDocument
+	for _, document := range fileDocuments {
+		if filtered {
+			for key, value := range document.values {
+				value.Value = applyResourceFiltering(value.Value, l.filteringProperties)
+				document.values[key] = value
+			}
+		}
+		documents = append(documents, document)
+		for _, importLocation := range GetPropertyValueList(document.values, "spring.config.import") {
+			for _, location := range l.resolveLocation(importLocation, filepath.Dir(path)) {
+				importedDocuments, err := l.loadLocation(location, "application", "")
+				if err != nil {
+					return nil, err
+				}
+				documents = append(documents, importedDocuments...)
+			}
+		}
+	}
+	return documents, nil
+}
+
+// skipOrReturnError records the malformed file and returns nil if skipMalformedFiles is enabled, otherwise returns
+// the error.
+func (l *configDataLoader) skipOrReturnError(err error) error {
+	var configFileError *ConfigFileError
+	if err != nil && l.skipMalformedFiles && errors.As(err, &configFileError) {
+		l.skippedFiles = append(l.skippedFiles, configFileError)
+		return nil
+	}
+	return err
+}
+
+// mergeDocuments merges documents ordered from low precedence to high precedence. Documents activated by
+// spring.config.activate.on-profile are merged only if they match the profiles.
+func mergeDocuments(documents []propertyDocument, profiles []string) PropertyValues {
+	result := make(PropertyValues)
+	for _, document := range documents {
+		if document.isActive(profiles) {
+			result.Merge(document.values)
+		}
+	}
+	return result
+}
+
+// withoutActivatedDocuments removes the documents activated by spring.config.activate.on-profile.
+func withoutActivatedDocuments(documents []propertyDocument) []propertyDocument {
+	var result []propertyDocument
+	for _, document := range documents {
+		if document.activationProfiles == "" {
+			result = append(result, document)
+		}
+	}
+	return result
+}
+
+func getSubDirectories(path string) []string {
+	entries, err := os.ReadDir(path)
+	if err != nil {
+		return nil
+	}
+	var result []string
+	for _, entry := range entries {
+		if entry.IsDir() {
+			result = append(result, filepath.Join(path, entry.Name()))
+		}
+	}
+	sort.Strings(result)
+	return result
+}
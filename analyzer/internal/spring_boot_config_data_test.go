@@ -0,0 +1,66 @@
+package internal
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadSpringBootConfig(t *testing.T) {
+	config, err := ReadSpringBootConfig(filepath.Join("testdata", "java-spring", "project-five"),
+		SpringBootConfigOptions{})
+	require.NoError(t, err)
+	properties := config.PropertyValues.ToMap()
+	require.Equal(t, "project-five", properties["spring.application.name"])
+	require.Equal(t, "bootstrap", properties["app.bootstrap-only"])
+	require.Equal(t, "file-config", properties["app.source"])
+	require.Equal(t, "dev", properties["app.profile"])
+	require.Equal(t, "application-dev", properties["app.imported"])
+	require.Equal(t, "hub-from-import", properties["spring.cloud.azure.eventhubs.event-hub-name"])
+	require.Equal(t, []string{"configserver:http://localhost:8888", "vault://"}, config.ExternalConfigImports)
+
+	resources := filepath.Join("testdata", "java-spring", "project-five", "src", "main", "resources")
+	imported := config.PropertyValues["app.imported"]
+	require.Equal(t, PropertyOrigin{FilePath: filepath.Join(resources, "application-dev.properties"), Line: 2,
+		Profile: "dev"}, imported.Origin)
+	require.Len(t, imported.Overrides, 2)
+	require.Equal(t, "azure", imported.Overrides[0].Value)
+	require.Equal(t, PropertyOrigin{FilePath: filepath.Join(resources, "azure.yml"), Line: 2, Column: 13},
+		imported.Overrides[0].Origin)
+	require.Equal(t, "application", imported.Overrides[1].Value)
+
+	document := config.PropertyValues["app.document"]
+	require.Equal(t, "dev", document.Value)
+	require.Equal(t, PropertyOrigin{FilePath: filepath.Join("testdata", "java-spring", "project-five", "config",
+		"application.yml"), Line: 13, Column: 13, DocumentIndex: 1, Profile: "dev"}, document.Origin)
+	require.Empty(t, document.Overrides)
+}
+
+func TestReadSpringBootConfigWithAdditionalLocation(t *testing.T) {
+	t.Setenv("SPRING_CONFIG_ADDITIONALLOCATION", "optional:file:./extra/")
+	properties, err := ReadProperties(filepath.Join("testdata", "java-spring", "project-five"))
+	require.NoError(t, err)
+	require.Equal(t, "additional-location", properties["app.source"])
+}
+
+func TestReadSpringBootConfigWithConfigLocation(t *testing.T) {
+	t.Setenv("SPRING_CONFIG_LOCATION", "optional:classpath:/")
+	properties, err := ReadProperties(filepath.Join("testdata", "java-spring", "project-five"))
+	require.NoError(t, err)
+	require.Equal(t, "classpath", properties["app.source"])
+	require.Equal(t, "bootstrap", properties["app.bootstrap-only"])
+}
+
+func TestImportedFileTakesPrecedenceOverImportingFile(t *testing.T) {
+	classpathRoot := filepath.Join("testdata", "java-spring", "project-five", "src", "main", "resources")
+	loader := configDataLoader{
+		projectPath:    filepath.Join("testdata", "java-spring", "project-five"),
+		resourceRoots:  []ResourceRoot{{Directory: classpathRoot}},
+		classpathRoots: []string{classpathRoot},
+		loadedFiles:    make(map[string]bool),
+	}
+	documents, err := loader.loadFile(filepath.Join(loader.classpathRoots[0], "application.properties"))
+	require.NoError(t, err)
+	require.Equal(t, "azure", mergeDocuments(documents, nil)["app.imported"].Value)
+}
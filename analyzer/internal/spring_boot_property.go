@@ -2,60 +2,144 @@ package internal
 
 import (
 	"bufio"
+	"bytes"
+	"errors"
 	"fmt"
-	"log"
+	"io"
 	"os"
-	"path/filepath"
 	"regexp"
+	"slices"
+	"sort"
+	"strconv"
 	"strings"
 
 	"github.com/braydonk/yaml"
 )
 
-func ReadProperties(projectPath string) map[string]string {
-	// todo: do we need to consider the bootstrap.properties
-	result := make(map[string]string)
-	readPropertiesInPropertiesFile(filepath.Join(projectPath, "/src/main/resources/application.properties"), result)
-	readPropertiesInYamlFile(filepath.Join(projectPath, "/src/main/resources/application.yml"), result)
-	readPropertiesInYamlFile(filepath.Join(projectPath, "/src/main/resources/application.yaml"), result)
-	profile, profileSet := result["spring.profiles.active"]
-	if profileSet {
-		readPropertiesInPropertiesFile(
-			filepath.Join(projectPath, "/src/main/resources/application-"+profile+".properties"), result)
-		readPropertiesInYamlFile(filepath.Join(projectPath, "/src/main/resources/application-"+profile+".yml"), result)
-		readPropertiesInYamlFile(filepath.Join(projectPath, "/src/main/resources/application-"+profile+".yaml"), result)
+// ReadProperties reads the properties of a Spring Boot project. See ReadSpringBootConfig for the loading order.
+func ReadProperties(projectPath string) (map[string]string, error) {
+	config, err := ReadSpringBootConfig(projectPath, SpringBootConfigOptions{})
+	if err != nil {
+		return nil, err
+	}
+	return config.PropertyValues.ToMap(), nil
+}
+
+// ConfigFileError is the error of reading or parsing a config file. Line and Column start from 1, and are 0 if unknown.
+type ConfigFileError struct {
+	FilePath string
+	Line     int
+	Column   int
+	Err      error
+}
+
+func (e *ConfigFileError) Error() string {
+	location := e.FilePath
+	if e.Line > 0 {
+		location = fmt.Sprintf("%s:%d", location, e.Line)
+		if e.Column > 0 {
+			location = fmt.Sprintf("%s:%d", location, e.Column)
+		}
+	}
+	return fmt.Sprintf("%s: %v", location, e.Err)
+}
+
+func (e *ConfigFileError) Unwrap() error {
+	return e.Err
+}
+
+var yamlErrorLocationRegex = regexp.MustCompile(`line (\d+)(?:, column (\d+))?`)
+
+func newYamlFileError(yamlFilePath string, err error) *ConfigFileError {
+	result := &ConfigFileError{FilePath: yamlFilePath, Err: err}
+	if match := yamlErrorLocationRegex.FindStringSubmatch(err.Error()); match != nil {
+		result.Line, _ = strconv.Atoi(match[1])
+		result.Column, _ = strconv.Atoi(match[2])
 	}
 	return result
 }
 
-func readPropertiesInYamlFile(yamlFilePath string, result map[string]string) {
+// propertyDocument is a document in a config file. Documents are separated by "---" in YAML files, and by "#---" or
+// "!---" in properties files.
+type propertyDocument struct {
+	values PropertyValues
+	// activationProfiles is the value of spring.config.activate.on-profile, like "dev" or "!prod". If set, the document
+	// is used only when the active profiles match it.
+	activationProfiles string
+}
+
+func newPropertyDocument(values PropertyValues) propertyDocument {
+	document := propertyDocument{values: values}
+	if activation, ok := values.Get("spring.config.activate.on-profile"); ok {
+		document.activationProfiles = activation.Value
+		for name, value := range values {
+			value.Origin.Profile = activation.Value
+			values[name] = value
+		}
+	}
+	return document
+}
+
+// isActive checks whether the document is used with the active profiles. Expressions like "dev", "dev,prod",
+// "dev | prod" and "!dev" are supported.
+func (d propertyDocument) isActive(profiles []string) bool {
+	if d.activationProfiles == "" {
+		return true
+	}
+	for _, expression := range strings.FieldsFunc(d.activationProfiles, func(r rune) bool {
+		return r == ',' || r == '|'
+	}) {
+		expression = strings.TrimSpace(expression)
+		if strings.HasPrefix(expression, "!") {
+			if !slices.Contains(profiles, strings.TrimSpace(expression[1:])) {
+				return true
+			}
+		} else if slices.Contains(profiles, expression) {
+			return true
+		}
+	}
+	return false
+}
+
+func readPropertiesInYamlFile(yamlFilePath string) ([]propertyDocument, error) {
 	if !fileExists(yamlFilePath) {
-		return
+		return nil, nil
 	}
 	data, err := os.ReadFile(yamlFilePath)
 	if err != nil {
-		log.Fatalf("error reading YAML file: %v", err)
-		return
+		return nil, &ConfigFileError{FilePath: yamlFilePath, Err: fmt.Errorf("reading YAML file: %w", err)}
 	}
+	return readPropertiesInYamlContent(yamlFilePath, data)
+}
 
-	// Parse the YAML into a yaml.Node
-	var root yaml.Node
-	err = yaml.Unmarshal(data, &root)
-	if err != nil {
-		log.Fatalf("error unmarshalling YAML: %v", err)
-		return
+// readPropertiesInYamlContent reads the properties in the YAML content, yamlFilePath is used in origins and errors.
+func readPropertiesInYamlContent(yamlFilePath string, data []byte) ([]propertyDocument, error) {
+	var documents []propertyDocument
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	for documentIndex := 0; ; documentIndex++ {
+		// Parse the YAML into a yaml.Node
+		var root yaml.Node
+		err := decoder.Decode(&root)
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, newYamlFileError(yamlFilePath, err)
+		}
+		values := make(PropertyValues)
+		parseYAML("", &root, values, PropertyOrigin{FilePath: yamlFilePath, DocumentIndex: documentIndex})
+		documents = append(documents, newPropertyDocument(values))
 	}
-
-	parseYAML("", &root, result)
+	return documents, nil
 }
 
 // Recursively parse the YAML and build dot-separated keys into a map
-func parseYAML(prefix string, node *yaml.Node, result map[string]string) {
+func parseYAML(prefix string, node *yaml.Node, result PropertyValues, origin PropertyOrigin) {
 	switch node.Kind {
 	case yaml.DocumentNode:
 		// Process each document's content
 		for _, contentNode := range node.Content {
-			parseYAML(prefix, contentNode, result)
+			parseYAML(prefix, contentNode, result, origin)
 		}
 	case yaml.MappingNode:
 		// Process key-value pairs in a map
@@ -73,36 +157,55 @@ func parseYAML(prefix string, node *yaml.Node, result map[string]string) {
 			if prefix != "" {
 				newPrefix = prefix + "." + keyStr
 			}
-			parseYAML(newPrefix, valueNode, result)
+			parseYAML(newPrefix, valueNode, result, origin)
 		}
 	case yaml.SequenceNode:
 		// Process items in a sequence (list)
 		for i, item := range node.Content {
 			newPrefix := fmt.Sprintf("%s[%d]", prefix, i)
-			parseYAML(newPrefix, item, result)
+			parseYAML(newPrefix, item, result, origin)
 		}
 	case yaml.ScalarNode:
 		// If it's a scalar value, add it to the result map
-		result[prefix] = getEnvironmentVariablePlaceholderHandledValue(node.Value)
+		origin.Line = node.Line
+		origin.Column = node.Column
+		result.Set(prefix, PropertyValue{
+			Value:  getEnvironmentVariablePlaceholderHandledValue(node.Value),
+			Origin: origin,
+		})
 	default:
 		// Handle other node types if necessary
 	}
 }
 
-func readPropertiesInPropertiesFile(propertiesFilePath string, result map[string]string) {
+func readPropertiesInPropertiesFile(propertiesFilePath string) ([]propertyDocument, error) {
 	if !fileExists(propertiesFilePath) {
-		return
+		return nil, nil
 	}
 	file, err := os.Open(propertiesFilePath)
 	if err != nil {
-		log.Fatalf("error opening properties file: %v", err)
-		return
+		return nil, &ConfigFileError{FilePath: propertiesFilePath,
+			Err: fmt.Errorf("opening properties file: %w", err)}
 	}
 	defer file.Close()
+	return readPropertiesInPropertiesContent(propertiesFilePath, file)
+}
 
-	scanner := bufio.NewScanner(file)
+// readPropertiesInPropertiesContent reads the properties in the content, propertiesFilePath is used in origins and
+// errors.
+func readPropertiesInPropertiesContent(propertiesFilePath string, reader io.Reader) ([]propertyDocument, error) {
+	var documents []propertyDocument
+	values := make(PropertyValues)
+	scanner := bufio.NewScanner(reader)
+	lineNumber := 0
 	for scanner.Scan() {
+		lineNumber++
 		line := scanner.Text()
+		if trimmed := strings.TrimSpace(line); trimmed == "#---" || trimmed == "!---" {
+			documents = append(documents, newPropertyDocument(values))
+			values = make(PropertyValues)
+			continue
+		}
 		if strings.TrimSpace(line) == "" || strings.HasPrefix(line, "#") {
 			continue
 		}
@@ -110,9 +213,21 @@ func readPropertiesInPropertiesFile(propertiesFilePath string, result map[string
 		if len(parts) == 2 {
 			key := strings.TrimSpace(parts[0])
 			value := getEnvironmentVariablePlaceholderHandledValue(parts[1])
-			result[key] = value
+			values.Set(key, PropertyValue{
+				Value: value,
+				Origin: PropertyOrigin{
+					FilePath:      propertiesFilePath,
+					Line:          lineNumber,
+					DocumentIndex: len(documents),
+				},
+			})
 		}
 	}
+	if err := scanner.Err(); err != nil {
+		return nil, &ConfigFileError{FilePath: propertiesFilePath, Line: lineNumber + 1,
+			Err: fmt.Errorf("scanning properties file: %w", err)}
+	}
+	return append(documents, newPropertyDocument(values)), nil
 }
 
 var environmentVariableRegex = regexp.MustCompile(`\$\{([^:}]+)(?::([^}]+))?}`)
@@ -137,7 +252,37 @@ func getEnvironmentVariablePlaceholderHandledValue(rawValue string) string {
 	return result
 }
 
+// DefaultServerPort is the HTTP port used by Spring Boot if server.port is not set.
+const DefaultServerPort = 8080
+
+// GetServerPort gets the HTTP port from server.port. Placeholders with default values like "${PORT:8081}" are resolved
+// when the properties are read, and profile-specific values already take precedence. DefaultServerPort is returned if
+// server.port is not set, is not a valid port, or is 0 which means a random port.
+func GetServerPort(properties PropertyValues) int {
+	return GetPort(properties, "server.port", DefaultServerPort)
+}
+
+// GetPort gets the port from the property, like quarkus.http.port of Quarkus. defaultPort is returned if the property
+// is not set, is not a valid port, or is 0 or negative which means a random port.
+func GetPort(properties PropertyValues, name string, defaultPort int) int {
+	value, ok := GetPropertyValue(properties, name)
+	if !ok {
+		return defaultPort
+	}
+	port, err := strconv.Atoi(strings.TrimSpace(value))
+	if err != nil || port <= 0 || port > 65535 {
+		return defaultPort
+	}
+	return port
+}
+
+// GetDatabaseName gets the database name from the path of the datasource URL, like "orders" in
+// "jdbc:postgresql://localhost:5432/orders", or from the databaseName or database property of SQL Server URLs like
+// "jdbc:sqlserver://localhost:1433;databaseName=orders". Empty if not found or not a valid database name.
 func GetDatabaseName(datasourceURL string) string {
+	if strings.HasPrefix(datasourceURL, "jdbc:sqlserver:") {
+		return getSqlServerDatabaseName(datasourceURL)
+	}
 	lastSlashIndex := strings.LastIndex(datasourceURL, "/")
 	if lastSlashIndex == -1 {
 		return ""
@@ -152,6 +297,20 @@ func GetDatabaseName(datasourceURL string) string {
 	return ""
 }
 
+func getSqlServerDatabaseName(datasourceURL string) string {
+	_, connectionProperties, _ := strings.Cut(datasourceURL, ";")
+	for _, property := range strings.Split(connectionProperties, ";") {
+		key, value, _ := strings.Cut(property, "=")
+		key = strings.TrimSpace(key)
+		if strings.EqualFold(key, "databaseName") || strings.EqualFold(key, "database") {
+			if value = strings.TrimSpace(value); IsValidDatabaseName(value) {
+				return value
+			}
+		}
+	}
+	return ""
+}
+
 func IsValidDatabaseName(name string) bool {
 	if len(name) < 3 || len(name) > 63 {
 		return false
@@ -160,20 +319,70 @@ func IsValidDatabaseName(name string) bool {
 	return re.MatchString(name)
 }
 
-func GetBindingDestinationMap(properties map[string]string) map[string]string {
+func GetBindingDestinationMap(properties PropertyValues) map[string]string {
 	result := make(map[string]string)
 	// Iterate through the properties map and look for matching keys
-	for key, value := range properties {
+	for canonicalKey, value := range properties {
 		// Check if the key matches the pattern `spring.cloud.stream.bindings.<binding-name>.destination`
-		if strings.HasPrefix(key, "spring.cloud.stream.bindings.") && strings.HasSuffix(key, ".destination") {
+		if strings.HasPrefix(canonicalKey, "spring.cloud.stream.bindings.") &&
+			strings.HasSuffix(canonicalKey, ".destination") {
 			// Store the binding name and destination value
-			result[key] = fmt.Sprintf("%v", value)
+			result[value.Name] = value.Value
+		}
+	}
+	return result
+}
+
+// StreamBinding is a binding of Spring Cloud Stream, like "consume-in-0".
+type StreamBinding struct {
+	// Name is in the canonical form of relaxed binding, like "consumein0".
+	Name        string
+	Destination string
+	// Group is the consumer group. Empty if not set.
+	Group string
+}
+
+// GetStreamBindings gets the bindings having spring.cloud.stream.bindings.<name>.destination, with the group in
+// spring.cloud.stream.bindings.<name>.group. The result is sorted by name.
+func GetStreamBindings(properties PropertyValues) []StreamBinding {
+	prefix := CanonicalPropertyName("spring.cloud.stream.bindings.")
+	destinations := make(map[string]string)
+	groups := make(map[string]string)
+	for canonicalKey, value := range properties {
+		if !strings.HasPrefix(canonicalKey, prefix) {
+			continue
+		}
+		name, property, ok := cutLast(strings.TrimPrefix(canonicalKey, prefix), ".")
+		if !ok {
+			continue
+		}
+		switch property {
+		case "destination":
+			destinations[name] = strings.TrimSpace(value.Value)
+		case "group":
+			groups[name] = strings.TrimSpace(value.Value)
 		}
 	}
+	var result []StreamBinding
+	for name, destination := range destinations {
+		if destination != "" {
+			result = append(result, StreamBinding{Name: name, Destination: destination, Group: groups[name]})
+		}
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Name < result[j].Name
+	})
 	return result
 }
 
-func GetDistinctBindingDestinationValues(properties map[string]string) []string {
+func cutLast(s string, separator string) (before string, after string, found bool) {
+	if index := strings.LastIndex(s, separator); index != -1 {
+		return s[:index], s[index+len(separator):], true
+	}
+	return s, "", false
+}
+
+func GetDistinctBindingDestinationValues(properties PropertyValues) []string {
 	return DistinctMapValues(GetBindingDestinationMap(properties))
 }
 
@@ -204,6 +413,16 @@ func AppendAndDistinct(a []string, b []string) []string {
 	return result
 }
 
+// AppendAndDistinctInOrder appends the values which don't exist in the slice, and keeps the order.
+func AppendAndDistinctInOrder(a []string, values ...string) []string {
+	for _, value := range values {
+		if !slices.Contains(a, value) {
+			a = append(a, value)
+		}
+	}
+	return a
+}
+
 func fileExists(filePath string) bool {
 	_, err := os.Stat(filePath)
 	return !os.IsNotExist(err)
@@ -1,27 +1,33 @@
 package internal
 
 import (
+	"errors"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/require"
 )
 
 func TestReadProperties(t *testing.T) {
-	var properties = ReadProperties(filepath.Join("testdata", "java-spring", "project-one"))
+	properties, err := ReadProperties(filepath.Join("testdata", "java-spring", "project-one"))
+	require.NoError(t, err)
 	require.Equal(t, "", properties["not.exist"])
 	require.Equal(t, "jdbc:h2:mem:testdb", properties["spring.datasource.url"])
 
-	properties = ReadProperties(filepath.Join("testdata", "java-spring", "project-two"))
+	properties, err = ReadProperties(filepath.Join("testdata", "java-spring", "project-two"))
+	require.NoError(t, err)
 	require.Equal(t, "", properties["not.exist"])
 	require.Equal(t, "jdbc:h2:mem:testdb", properties["spring.datasource.url"])
 
-	properties = ReadProperties(filepath.Join("testdata", "java-spring", "project-three"))
+	properties, err = ReadProperties(filepath.Join("testdata", "java-spring", "project-three"))
+	require.NoError(t, err)
 	require.Equal(t, "", properties["not.exist"])
 	require.Equal(t, "HTML", properties["spring.thymeleaf.mode"])
 
-	properties = ReadProperties(filepath.Join("testdata", "java-spring", "project-four"))
+	properties, err = ReadProperties(filepath.Join("testdata", "java-spring", "project-four"))
+	require.NoError(t, err)
 	require.Equal(t, "", properties["not.exist"])
 	require.Equal(t, "mysql", properties["database"])
 }
@@ -88,6 +94,28 @@ func TestGetEnvironmentVariablePlaceholderHandledValue(t *testing.T) {
 	}
 }
 
+func TestGetServerPort(t *testing.T) {
+	properties, err := ReadProperties(filepath.Join("testdata", "java-spring", "project-four"))
+	require.NoError(t, err)
+	require.Equal(t, 8081, GetServerPort(NewTestPropertyValues(properties)))
+
+	tests := []struct {
+		name       string
+		properties map[string]string
+		expected   int
+	}{
+		{"not set", map[string]string{}, DefaultServerPort},
+		{"environment variable style", map[string]string{"SERVER_PORT": "9090"}, 9090},
+		{"random port", map[string]string{"server.port": "0"}, DefaultServerPort},
+		{"invalid", map[string]string{"server.port": "http"}, DefaultServerPort},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.expected, GetServerPort(NewTestPropertyValues(tt.properties)))
+		})
+	}
+}
+
 func TestGetDatabaseName(t *testing.T) {
 	tests := []struct {
 		input    string
@@ -108,6 +136,11 @@ func TestGetDatabaseName(t *testing.T) {
 			"jdbc:postgresql://your_postgresql_server.postgres.database.azure.com:5432/your-database-name" +
 				"?sslmode=require&spring.datasource.azure.passwordless-enabled=true", "your-database-name",
 		},
+		{"jdbc:mariadb://localhost:3306/your-database-name", "your-database-name"},
+		{"jdbc:sqlserver://localhost:1433;databaseName=your-database-name;encrypt=true", "your-database-name"},
+		{"jdbc:sqlserver://your_server.database.windows.net:1433;encrypt=true;database=your-database-name",
+			"your-database-name"},
+		{"jdbc:sqlserver://localhost:1433;encrypt=true", ""},
 	}
 	for _, test := range tests {
 		result := GetDatabaseName(test.input)
@@ -146,3 +179,90 @@ func TestIsValidDatabaseName(t *testing.T) {
 		})
 	}
 }
+
+func TestReadPropertiesWithMalformedFile(t *testing.T) {
+	projectPath := filepath.Join("testdata", "java-spring", "project-seven")
+	yamlFilePath := filepath.Join(projectPath, "src", "main", "resources", "application.yml")
+	_, err := ReadProperties(projectPath)
+	var configFileError *ConfigFileError
+	require.ErrorAs(t, err, &configFileError)
+	require.Equal(t, yamlFilePath, configFileError.FilePath)
+	require.Equal(t, 4, configFileError.Line)
+	require.Contains(t, err.Error(), yamlFilePath+":4: ")
+
+	config, err := ReadSpringBootConfig(projectPath, SpringBootConfigOptions{SkipMalformedFiles: true})
+	require.NoError(t, err)
+	require.Equal(t, "project-seven", config.PropertyValues.ToMap()["spring.application.name"])
+	require.Equal(t, "", config.PropertyValues.ToMap()["spring.datasource.url"])
+	require.Len(t, config.SkippedFiles, 1)
+	require.Equal(t, yamlFilePath, config.SkippedFiles[0].FilePath)
+}
+
+func TestConfigFileError(t *testing.T) {
+	err := &ConfigFileError{FilePath: "application.yml", Line: 2, Column: 3, Err: errors.New("bad")}
+	require.Equal(t, "application.yml:2:3: bad", err.Error())
+	err = &ConfigFileError{FilePath: "application.yml", Err: errors.New("bad")}
+	require.Equal(t, "application.yml: bad", err.Error())
+}
+
+func TestReadPropertiesInYamlContentWithMultipleDocuments(t *testing.T) {
+	content := `app:
+  name: default
+---
+spring:
+  config:
+    activate:
+      on-profile: dev
+app:
+  name: dev
+`
+	documents, err := readPropertiesInYamlContent("application.yml", []byte(content))
+	require.NoError(t, err)
+	require.Len(t, documents, 2)
+	require.Equal(t, "", documents[0].activationProfiles)
+	require.Equal(t, "dev", documents[1].activationProfiles)
+	require.Equal(t, PropertyOrigin{FilePath: "application.yml", Line: 9, Column: 9, DocumentIndex: 1, Profile: "dev"},
+		documents[1].values["app.name"].Origin)
+
+	require.Equal(t, "default", mergeDocuments(documents, nil)["app.name"].Value)
+	require.Equal(t, "dev", mergeDocuments(documents, []string{"dev"})["app.name"].Value)
+}
+
+func TestReadPropertiesInPropertiesContentWithMultipleDocuments(t *testing.T) {
+	content := `app.name=default
+#---
+spring.config.activate.on-profile=!dev
+app.name=not-dev
+`
+	documents, err := readPropertiesInPropertiesContent("application.properties", strings.NewReader(content))
+	require.NoError(t, err)
+	require.Len(t, documents, 2)
+	require.Equal(t, PropertyOrigin{FilePath: "application.properties", Line: 1}, documents[0].values["app.name"].Origin)
+	require.Equal(t, PropertyOrigin{FilePath: "application.properties", Line: 4, DocumentIndex: 1, Profile: "!dev"},
+		documents[1].values["app.name"].Origin)
+
+	require.Equal(t, "not-dev", mergeDocuments(documents, nil)["app.name"].Value)
+	require.Equal(t, "default", mergeDocuments(documents, []string{"dev"})["app.name"].Value)
+}
+
+func TestPropertyDocumentIsActive(t *testing.T) {
+	tests := []struct {
+		activationProfiles string
+		profiles           []string
+		expected           bool
+	}{
+		{"", nil, true},
+		{"dev", nil, false},
+		{"dev", []string{"dev"}, true},
+		{"dev,prod", []string{"prod"}, true},
+		{"dev | prod", []string{"test"}, false},
+		{"!dev", []string{"prod"}, true},
+		{"!dev", []string{"dev"}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.activationProfiles, func(t *testing.T) {
+			document := propertyDocument{activationProfiles: tt.activationProfiles}
+			require.Equal(t, tt.expected, document.isActive(tt.profiles))
+		})
+	}
+}
@@ -0,0 +1,91 @@
+package internal
+
+import (
+	"net"
+	"net/url"
+	"sort"
+	"strings"
+)
+
+// gatewayRoutePropertyPrefixes are the prefixes of Spring Cloud Gateway route properties. The second and third are
+// used since Spring Cloud Gateway 4.3.
+var gatewayRoutePropertyPrefixes = []string{
+	"spring.cloud.gateway.routes[",
+	"spring.cloud.gateway.server.webflux.routes[",
+	"spring.cloud.gateway.server.webmvc.routes[",
+}
+
+// GetGatewayRouteServiceIds gets the service ids in load-balanced route URIs like
+// spring.cloud.gateway.routes[0].uri=lb://customers-service, in alphabetical order.
+func GetGatewayRouteServiceIds(properties PropertyValues) []string {
+	var result []string
+	for canonicalKey, value := range properties {
+		if !strings.HasSuffix(canonicalKey, "].uri") || !hasAnyPrefix(canonicalKey, gatewayRoutePropertyPrefixes) {
+			continue
+		}
+		if serviceId, ok := getLoadBalancedServiceId(value.Value); ok {
+			result = AppendAndDistinctInOrder(result, serviceId)
+		}
+	}
+	sort.Strings(result)
+	return result
+}
+
+// getLoadBalancedServiceId gets "customers-service" from "lb://customers-service" or "lb://customers-service/path".
+func getLoadBalancedServiceId(uri string) (string, bool) {
+	uri = strings.TrimSpace(uri)
+	if !strings.HasPrefix(strings.ToLower(uri), "lb://") {
+		return "", false
+	}
+	serviceId := uri[len("lb://"):]
+	if index := strings.IndexAny(serviceId, "/:?"); index != -1 {
+		serviceId = serviceId[:index]
+	}
+	return serviceId, serviceId != ""
+}
+
+func hasAnyPrefix(value string, prefixes []string) bool {
+	for _, prefix := range prefixes {
+		if strings.HasPrefix(value, CanonicalPropertyName(prefix)) {
+			return true
+		}
+	}
+	return false
+}
+
+// GetUrlServiceReferences gets the host names in property values which look like URLs of other applications, like
+// "http://customers-service", "https://vets-service:8081/vets" or "lb://visits-service". Property values of
+// spring.cloud.openfeign.client.config.{name}.url are examples. Hosts which look like external domains, IP addresses
+// or localhost are ignored. The result is in alphabetical order.
+func GetUrlServiceReferences(properties PropertyValues) []string {
+	var result []string
+	for _, value := range properties {
+		for _, item := range strings.Split(value.Value, ",") {
+			if host, ok := GetUrlServiceHost(item); ok {
+				result = AppendAndDistinctInOrder(result, host)
+			}
+		}
+	}
+	sort.Strings(result)
+	return result
+}
+
+// GetUrlServiceHost gets the host name in URLs like "http://customers-service:8081/owners", if the host looks like
+// the name of an application.
+func GetUrlServiceHost(value string) (string, bool) {
+	value = strings.TrimSpace(value)
+	lowerValue := strings.ToLower(value)
+	if !strings.HasPrefix(lowerValue, "http://") && !strings.HasPrefix(lowerValue, "https://") &&
+		!strings.HasPrefix(lowerValue, "lb://") {
+		return "", false
+	}
+	parsed, err := url.Parse(value)
+	if err != nil {
+		return "", false
+	}
+	host := parsed.Hostname()
+	if host == "" || strings.Contains(host, ".") || strings.EqualFold(host, "localhost") || net.ParseIP(host) != nil {
+		return "", false
+	}
+	return host, true
+}
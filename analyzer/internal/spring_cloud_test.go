@@ -0,0 +1,37 @@
+package internal
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetGatewayRouteServiceIds(t *testing.T) {
+	properties := NewTestPropertyValues(map[string]string{
+		"spring.cloud.gateway.routes[0].id":                        "vets-service",
+		"spring.cloud.gateway.routes[0].uri":                       "lb://vets-service",
+		"spring.cloud.gateway.routes[1].uri":                       "lb://customers-service/owners",
+		"spring.cloud.gateway.server.webflux.routes[0].uri":        "lb://visits-service",
+		"SPRING_CLOUD_GATEWAY_ROUTES_2_URI":                        "lb://vets-service",
+		"spring.cloud.gateway.routes[3].uri":                       "https://example.com",
+		"spring.cloud.gateway.routes[4].predicates[0]":             "Path=/api/**",
+		"spring.cloud.gateway.default-filters[0].args.fallbackUri": "lb://ignored",
+	})
+	require.Equal(t, []string{"customers-service", "vets-service", "visits-service"},
+		GetGatewayRouteServiceIds(properties))
+}
+
+func TestGetUrlServiceReferences(t *testing.T) {
+	properties := NewTestPropertyValues(map[string]string{
+		"spring.cloud.openfeign.client.config.vets.url": "http://vets-service:8081",
+		"app.visits.base-url":                           "https://visits-service/api",
+		"eureka.client.service-url.defaultZone":         "http://discovery-server:8761/eureka/",
+		"app.urls":                                      "lb://customers-service, http://visits-service",
+		"app.external":                                  "https://api.example.com",
+		"app.local":                                     "http://localhost:8080",
+		"app.ip":                                        "http://127.0.0.1:8080",
+		"spring.datasource.url":                         "jdbc:mysql://mysql-server:3306/db",
+	})
+	require.Equal(t, []string{"customers-service", "discovery-server", "vets-service", "visits-service"},
+		GetUrlServiceReferences(properties))
+}
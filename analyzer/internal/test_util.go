@@ -1,6 +1,7 @@
 package internal
 
 import (
+	"encoding/xml"
 	"os"
 	"path/filepath"
 )
@@ -28,3 +29,19 @@ func PrepareTestPomFiles(testPoms []TestPom) (string, error) {
 	}
 	return tempDir, nil
 }
+
+// ParseTestPom parses the pom content without creating the effective pom, so Maven is not required.
+func ParseTestPom(pomContentString string) (Pom, error) {
+	var result Pom
+	err := xml.Unmarshal([]byte(pomContentString), &result)
+	return result, err
+}
+
+// NewTestPropertyValues converts property name -> value to PropertyValues without origins.
+func NewTestPropertyValues(properties map[string]string) PropertyValues {
+	result := make(PropertyValues, len(properties))
+	for name, value := range properties {
+		result.Set(name, PropertyValue{Value: value})
+	}
+	return result
+}
@@ -0,0 +1,22 @@
+package com.example.orders;
+
+import org.springframework.boot.SpringApplication;
+import org.springframework.boot.autoconfigure.SpringBootApplication;
+import org.springframework.jms.annotation.JmsListener;
+
+@SpringBootApplication
+public class OrdersApplication {
+
+    public static void main(String[] args) {
+        SpringApplication.run(OrdersApplication.class, args);
+    }
+
+    // @JmsListener(destination = "commented-out")
+    @JmsListener(destination = "${app.queue:orders-queue}")
+    public void receiveOrder(String order) {
+    }
+
+    @JmsListener(destination = "order-" + "events", subscription = "audit", containerFactory = TOPIC_FACTORY)
+    public void auditOrder(String order) {
+    }
+}
@@ -0,0 +1,15 @@
+package com.example.orders
+
+import org.springframework.kafka.annotation.KafkaListener
+import org.springframework.stereotype.Component
+
+/*
+ * @KafkaListener(topics = ["commented-out"])
+ */
+@Component
+class PaymentListener {
+
+    @KafkaListener(topics = ["payments", "refunds"], groupId = "orders")
+    fun onPayment(payment: String) {
+    }
+}
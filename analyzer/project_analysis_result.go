@@ -1,6 +1,11 @@
 package analyzer
 
-import "fmt"
+import (
+	"fmt"
+	"slices"
+
+	"ajpa/analyzer/internal"
+)
 
 type ProjectAnalysisResult struct {
 	Name                        string
@@ -8,19 +13,215 @@ type ProjectAnalysisResult struct {
 	Services                    map[string]Service                // service name -> Service
 	ApplicationToHostingService map[string]string                 // application name -> hosting Service name
 	ApplicationToBackingService map[string]map[string]interface{} // application name -> backing Service names (set)
+	ApplicationToApplication    map[string]map[string]interface{} // application name -> used application names (set)
+	Diagnostics                 []Diagnostic
 }
 
 type Application struct {
 	// todo: add other fields like Dockerfile path
 	ProjectRelativePath string
+	// Framework is the application framework, like Spring Boot or Quarkus.
+	Framework Framework
+	// ExternalConfigSources are config sources imported by spring.config.import which can not be analyzed locally,
+	// like "configserver:http://localhost:8888" or "vault://".
+	ExternalConfigSources []string
+	// Properties are the resolved properties of the application, canonical property name -> PropertyValue. See
+	// internal.CanonicalPropertyName for the canonical name under relaxed binding.
+	Properties map[string]PropertyValue
+	// WebApplicationType is how the application serves HTTP. WebApplicationTypeNone means it doesn't serve HTTP, like
+	// workers which only consume queues.
+	WebApplicationType WebApplicationType
+	// Port is the HTTP port of web applications, from server.port, quarkus.http.port or micronaut.server.port. 0 if
+	// the application doesn't serve HTTP.
+	Port int
+	// HealthProbes are the endpoints used by container probes, from Spring Boot Actuator, SmallRye Health of Quarkus or
+	// Micronaut management. Nil if the application doesn't serve the health endpoint over HTTP.
+	HealthProbes *HealthProbes
+	// SpringCloudComponents are the Spring Cloud roles of the application, like gateway or discovery server.
+	SpringCloudComponents []SpringCloudComponent
+	// ServiceReferences are the service ids or host names of other applications called by this application, from
+	// gateway routes like "lb://customers-service", @FeignClient names, and property values like
+	// "http://vets-service". They are resolved to ApplicationToApplication after all applications are analyzed, and
+	// references which don't match any application are ignored.
+	ServiceReferences []string
+	// Ingress is whether the application is reachable from the internet or only from other applications. Empty if the
+	// application doesn't serve HTTP.
+	Ingress IngressType
+	// JavaVersion is the Java release like "17", used to choose the JDK of the image. Empty if not declared.
+	JavaVersion string
+	// SpringBootVersion is like "3.3.0". Empty if not found.
+	SpringBootVersion string
+	// Datasources are the databases configured by datasource URLs, see detectDatasources.
+	Datasources []Datasource
+	// KeyVaultProperties are the property names expected to come from Key Vault secrets, when Key Vault property
+	// sources are configured, see internal.GetKeyVaultSecretProperties.
+	KeyVaultProperties []string
+	// ServiceEvidence is why the backing services are detected, backing service name -> Evidence.
+	ServiceEvidence map[string][]Evidence
+}
+
+type Framework string
+
+const (
+	FrameworkSpringBoot Framework = "spring-boot"
+	FrameworkQuarkus    Framework = "quarkus"
+	FrameworkMicronaut  Framework = "micronaut"
+	// FrameworkAzureFunctions is used by Azure Functions, which are hosted by AzureFunctionApp instead of
+	// AzureContainerApp.
+	FrameworkAzureFunctions Framework = "azure-functions"
+)
+
+type EvidenceKind string
+
+const (
+	EvidenceKindDependency EvidenceKind = "dependency"
+	EvidenceKindPlugin     EvidenceKind = "plugin"
+	EvidenceKindProperty   EvidenceKind = "property"
+	EvidenceKindAnnotation EvidenceKind = "annotation"
+	// EvidenceKindCondition is a condition implemented in code, like Kafka clients connecting to Event Hubs.
+	EvidenceKindCondition EvidenceKind = "condition"
+)
+
+// Evidence is a dependency, plugin, property or annotation which makes a service detected.
+type Evidence struct {
+	Kind EvidenceKind
+	// Value is like "org.postgresql:postgresql" for dependencies and plugins, "spring.datasource.url" for properties,
+	// and "JmsListener" for annotations.
+	Value string
+	// FilePath is relative to the project root path, like the build file of dependencies. Line starts from 1, and is 0
+	// if unknown.
+	FilePath string
+	Line     int
+}
+
+func (e Evidence) String() string {
+	result := fmt.Sprintf("%s %s", e.Kind, e.Value)
+	if e.FilePath == "" {
+		return result
+	}
+	location := e.FilePath
+	if e.Line > 0 {
+		location = fmt.Sprintf("%s:%d", location, e.Line)
+	}
+	return fmt.Sprintf("%s (%s)", result, location)
+}
+
+// Datasource is a database connection configured by a datasource URL property, like spring.datasource.url.
+type Datasource struct {
+	// PropertyName is the name of the URL property.
+	PropertyName string
+	// Vendor is like "postgresql", "mysql", "mariadb", "sqlserver", "oracle" or "h2".
+	Vendor string
+	// Host is empty for embedded databases like H2. Port is 0 if not set.
+	Host         string
+	Port         int
+	DatabaseName string
+	// ServiceName is the backing service of the database. Empty for embedded databases and unsupported databases like
+	// Oracle.
+	ServiceName string
+}
+
+type SpringCloudComponent string
+
+const (
+	SpringCloudComponentGateway         SpringCloudComponent = "gateway"
+	SpringCloudComponentDiscoveryServer SpringCloudComponent = "discovery-server"
+	SpringCloudComponentDiscoveryClient SpringCloudComponent = "discovery-client"
+	SpringCloudComponentConfigServer    SpringCloudComponent = "config-server"
+	SpringCloudComponentConfigClient    SpringCloudComponent = "config-client"
+)
+
+type IngressType string
+
+const (
+	IngressTypeExternal IngressType = "external"
+	IngressTypeInternal IngressType = "internal"
+)
+
+// HealthProbes are the HTTP endpoints used by liveness, readiness and startup probes.
+type HealthProbes struct {
+	Port          int
+	LivenessPath  string
+	ReadinessPath string
+	StartupPath   string
+}
+
+// WebApplicationType is the same as Spring Boot's WebApplicationType.
+type WebApplicationType string
+
+const (
+	WebApplicationTypeServlet  WebApplicationType = "servlet"
+	WebApplicationTypeReactive WebApplicationType = "reactive"
+	WebApplicationTypeNone     WebApplicationType = "none"
+)
+
+// PropertyValue is a resolved property value and where it's defined.
+type PropertyValue struct {
+	// Name is the property name as it's defined, like "spring.datasource.url" or "SPRING_DATASOURCE_URL".
+	Name   string
+	Value  string
+	Origin PropertyOrigin
+	// Overrides are the values of the same property overridden by this value, ordered from high precedence to low
+	// precedence.
+	Overrides []PropertyValue
+}
+
+// PropertyOrigin is where a property value is defined.
+type PropertyOrigin struct {
+	// FilePath is relative to the project root path. For values not read from a file, like ConfigMap entries, it also
+	// describes the entry. Line and Column start from 1, and are 0 if unknown.
+	FilePath string
+	Line     int
+	Column   int
+	// DocumentIndex is the index of the document in multi-document files, starts from 0.
+	DocumentIndex int
+	// Profile is the profile of the profile-specific file or of the document activated by
+	// spring.config.activate.on-profile. Empty if it's not profile-specific.
+	Profile string
+}
+
+func (o PropertyOrigin) String() string {
+	return internal.PropertyOrigin(o).String()
 }
 
 type Service interface {
 }
 
+type DiagnosticSeverity string
+
+const (
+	DiagnosticSeverityWarning DiagnosticSeverity = "warning"
+	DiagnosticSeverityInfo    DiagnosticSeverity = "info"
+)
+
+// Diagnostic is a problem found when analyzing the project, which doesn't stop the analysis.
+type Diagnostic struct {
+	Severity        DiagnosticSeverity
+	ApplicationName string
+	// FilePath is relative to the project root path. Line and Column start from 1, and are 0 if unknown.
+	FilePath string
+	Line     int
+	Column   int
+	Message  string
+}
+
+func (d Diagnostic) String() string {
+	location := d.FilePath
+	if d.Line > 0 {
+		location = fmt.Sprintf("%s:%d", location, d.Line)
+		if d.Column > 0 {
+			location = fmt.Sprintf("%s:%d", location, d.Column)
+		}
+	}
+	return fmt.Sprintf("%s: %s (application: %s): %s", d.Severity, location, d.ApplicationName, d.Message)
+}
+
 type AzureContainerApp struct { // todo: Support other hosting Service like AKS.
 }
 
+type AzureFunctionApp struct {
+}
+
 const DefaultPostgresqlServiceName string = "postgresql"
 
 type AzureDatabaseForPostgresql struct {
@@ -35,6 +236,19 @@ type AzureDatabaseForMysql struct {
 	DatabaseName string
 }
 
+const DefaultSqlServerServiceName = "sql-server"
+
+// AzureSqlDatabase is a database of Azure SQL, used by SQL Server clients.
+type AzureSqlDatabase struct {
+	DatabaseName string
+}
+
+const DefaultMariadbServiceName = "mariadb"
+
+type AzureDatabaseForMariadb struct {
+	DatabaseName string
+}
+
 const DefaultRedisServiceName = "redis"
 
 type AzureCacheForRedis struct {
@@ -51,19 +265,23 @@ const DefaultCosmosServiceName = "cosmos"
 
 type AzureCosmosDb struct {
 	// todo: Add fields like auth type
+	Databases  []string
+	Containers []string
 }
 
 const DefaultServiceBusServiceName = "service-bus"
 
 type AzureServiceBus struct {
-	Queues []string
-	Topics []string
+	Queues        []string
+	Topics        []string
+	Subscriptions map[string][]string // topic name -> subscription names
 }
 
 const DefaultEventHubsServiceName = "event-hubs"
 
 type AzureEventHubs struct {
-	Hubs []string
+	Hubs           []string
+	ConsumerGroups map[string][]string // hub name -> consumer group names
 }
 
 const DefaultStorageServiceName = "storage"
@@ -72,6 +290,38 @@ type AzureStorageAccount struct {
 	Containers []string
 }
 
+// AzureOpenAiModel is a model deployment of Azure OpenAI. Its service name is the deployment name used by the
+// application, so there is no default service name.
+type AzureOpenAiModel struct {
+	Name string
+	// Version is empty if the model is unknown.
+	Version string
+}
+
+const DefaultAppConfigurationServiceName = "app-configuration"
+
+type AzureAppConfiguration struct {
+	Stores []AzureAppConfigurationStore
+}
+
+// AzureAppConfigurationStore is a store read by the application.
+type AzureAppConfigurationStore struct {
+	// Endpoint is like "https://contoso.azconfig.io". Empty if it's not set.
+	Endpoint string
+	// KeyPrefixes are the key filters of the selects, like "/orders/". Empty means the default "/application/".
+	KeyPrefixes []string
+	// LabelFilters are the label filters of the selects, like "prod".
+	LabelFilters        []string
+	FeatureFlagsEnabled bool
+}
+
+const DefaultKeyVaultServiceName = "key-vault"
+
+type AzureKeyVault struct {
+	// Endpoints are the vault URIs configured by the application, like "https://orders.vault.azure.net/".
+	Endpoints []string
+}
+
 func addApplicationToResult(result *ProjectAnalysisResult, applicationName string, application Application) error {
 	if _, ok := result.Applications[applicationName]; ok {
 		return fmt.Errorf("applicationName %s already exists", applicationName)
@@ -118,8 +368,9 @@ func addApplicationRelatedBackingServiceToResult(result *ProjectAnalysisResult,
 	if result.Services == nil {
 		result.Services = make(map[string]Service)
 	}
-	if _, ok := result.Services[backingServiceName]; ok {
-		// todo: merge properties (like database name)
+	if service, ok := result.Services[backingServiceName]; ok {
+		// todo: merge other properties (like database name)
+		result.Services[backingServiceName] = mergeServices(service, backingService)
 	} else {
 		result.Services[backingServiceName] = backingService
 	}
@@ -137,6 +388,72 @@ func addApplicationRelatedBackingServiceToResult(result *ProjectAnalysisResult,
 	return nil
 }
 
+// mergeServices merges the entities of the other service of the same name into the service, like the queues of
+// Service Bus or the containers of a storage account. The service is kept as it is if it has no entities or the types
+// differ.
+func mergeServices(service Service, other Service) Service {
+	switch s := service.(type) {
+	case AzureServiceBus:
+		if o, ok := other.(AzureServiceBus); ok {
+			var entities serviceBusEntities
+			for _, serviceBus := range []AzureServiceBus{s, o} {
+				for _, queue := range serviceBus.Queues {
+					entities.addQueue(queue)
+				}
+				for _, topic := range serviceBus.Topics {
+					entities.addTopic(topic, "")
+					for _, subscription := range serviceBus.Subscriptions[topic] {
+						entities.addTopic(topic, subscription)
+					}
+				}
+			}
+			return entities.toService()
+		}
+	case AzureEventHubs:
+		if o, ok := other.(AzureEventHubs); ok {
+			var entities eventHubsEntities
+			for _, eventHubs := range []AzureEventHubs{s, o} {
+				for _, hub := range eventHubs.Hubs {
+					entities.addHubs([]string{hub}, eventHubs.ConsumerGroups[hub])
+				}
+			}
+			return entities.toService()
+		}
+	case AzureStorageAccount:
+		if o, ok := other.(AzureStorageAccount); ok {
+			s.Containers = internal.AppendAndDistinctInOrder(slices.Clone(s.Containers), o.Containers...)
+			return s
+		}
+	case AzureCosmosDb:
+		if o, ok := other.(AzureCosmosDb); ok {
+			s.Databases = internal.AppendAndDistinctInOrder(slices.Clone(s.Databases), o.Databases...)
+			s.Containers = internal.AppendAndDistinctInOrder(slices.Clone(s.Containers), o.Containers...)
+			return s
+		}
+	}
+	return service
+}
+
+func addApplicationToApplicationEdgeToResult(result *ProjectAnalysisResult, applicationName string,
+	usedApplicationName string) error {
+	// 1. Check both applications exist
+	if _, ok := result.Applications[applicationName]; !ok {
+		return fmt.Errorf("applicationName %s doesn't exist", applicationName)
+	}
+	if _, ok := result.Applications[usedApplicationName]; !ok {
+		return fmt.Errorf("usedApplicationName %s doesn't exist", usedApplicationName)
+	}
+	// 2. Add Application to Application mapping
+	if result.ApplicationToApplication == nil {
+		result.ApplicationToApplication = make(map[string]map[string]interface{})
+	}
+	if result.ApplicationToApplication[applicationName] == nil {
+		result.ApplicationToApplication[applicationName] = make(map[string]interface{})
+	}
+	result.ApplicationToApplication[applicationName][usedApplicationName] = ""
+	return nil
+}
+
 func mergeProjectAnalysisResult(result1 ProjectAnalysisResult, result2 ProjectAnalysisResult) (ProjectAnalysisResult,
 	error) {
 	if result1.Name != result2.Name {
@@ -149,6 +466,7 @@ func mergeProjectAnalysisResult(result1 ProjectAnalysisResult, result2 ProjectAn
 			result1.Name = result2.Name
 		}
 	}
+	result2 = renameConflictingDatabaseServices(result1, result2)
 	// 1. Add application
 	for applicationName, application := range result2.Applications {
 		err := addApplicationToResult(&result1, applicationName, application)
@@ -182,5 +500,80 @@ func mergeProjectAnalysisResult(result1 ProjectAnalysisResult, result2 ProjectAn
 			}
 		}
 	}
+	// 4. Add diagnostics
+	result1.Diagnostics = append(result1.Diagnostics, result2.Diagnostics...)
 	return result1, nil
 }
+
+// renameConflictingDatabaseServices renames the database services of result2 whose names are used by different
+// databases in result1. Each module names the first database of a vendor by the default service name, see
+// detectDatasources, so modules using different databases would otherwise share one service. The renamed services are
+// suffixed by their database names like "postgresql-inventory", so modules using the same database still share it.
+func renameConflictingDatabaseServices(result1 ProjectAnalysisResult,
+	result2 ProjectAnalysisResult) ProjectAnalysisResult {
+	newNames := make(map[string]string) // service name -> new service name
+	for name, service := range result2.Services {
+		databaseName, ok := getDatabaseName(service)
+		if !ok || databaseName == "" {
+			continue
+		}
+		if existingDatabaseName, ok := getDatabaseName(result1.Services[name]); ok && existingDatabaseName != "" &&
+			existingDatabaseName != databaseName {
+			newNames[name] = name + "-" + databaseName
+		}
+	}
+	if len(newNames) == 0 {
+		return result2
+	}
+	rename := func(name string) string {
+		if newName, ok := newNames[name]; ok {
+			return newName
+		}
+		return name
+	}
+	services := make(map[string]Service, len(result2.Services))
+	for name, service := range result2.Services {
+		services[rename(name)] = service
+	}
+	result2.Services = services
+	applicationToBackingService := make(map[string]map[string]interface{}, len(result2.ApplicationToBackingService))
+	for applicationName, serviceNames := range result2.ApplicationToBackingService {
+		applicationToBackingService[applicationName] = make(map[string]interface{}, len(serviceNames))
+		for name := range serviceNames {
+			applicationToBackingService[applicationName][rename(name)] = ""
+		}
+	}
+	result2.ApplicationToBackingService = applicationToBackingService
+	applications := make(map[string]Application, len(result2.Applications))
+	for applicationName, application := range result2.Applications {
+		application.Datasources = slices.Clone(application.Datasources)
+		for i, datasource := range application.Datasources {
+			application.Datasources[i].ServiceName = rename(datasource.ServiceName)
+		}
+		if application.ServiceEvidence != nil {
+			serviceEvidence := make(map[string][]Evidence, len(application.ServiceEvidence))
+			for name, evidence := range application.ServiceEvidence {
+				serviceEvidence[rename(name)] = append(serviceEvidence[rename(name)], evidence...)
+			}
+			application.ServiceEvidence = serviceEvidence
+		}
+		applications[applicationName] = application
+	}
+	result2.Applications = applications
+	return result2
+}
+
+// getDatabaseName gets the database name of the database services, false is returned for other services.
+func getDatabaseName(service Service) (string, bool) {
+	switch s := service.(type) {
+	case AzureDatabaseForPostgresql:
+		return s.DatabaseName, true
+	case AzureDatabaseForMysql:
+		return s.DatabaseName, true
+	case AzureSqlDatabase:
+		return s.DatabaseName, true
+	case AzureDatabaseForMariadb:
+		return s.DatabaseName, true
+	}
+	return "", false
+}
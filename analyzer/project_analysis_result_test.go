@@ -0,0 +1,112 @@
+package analyzer
+
+import (
+	"testing"
+
+	"ajpa/analyzer/internal"
+
+	"github.com/stretchr/testify/require"
+)
+
+// newTestModuleResult is the result of a module having an application using the backing service.
+func newTestModuleResult(t *testing.T, applicationName string, backingServiceName string,
+	backingService Service) ProjectAnalysisResult {
+	result := ProjectAnalysisResult{Name: "project"}
+	require.NoError(t, addApplicationToResult(&result, applicationName, Application{}))
+	require.NoError(t, addApplicationRelatedHostingServiceToResult(&result, applicationName, applicationName,
+		AzureContainerApp{}))
+	require.NoError(t, addApplicationRelatedBackingServiceToResult(&result, applicationName, backingServiceName,
+		backingService))
+	return result
+}
+
+func TestMergeProjectAnalysisResultWithServiceBus(t *testing.T) {
+	orders := newTestModuleResult(t, "orders", DefaultServiceBusServiceName, AzureServiceBus{
+		Queues:        []string{"orders"},
+		Topics:        []string{"notifications"},
+		Subscriptions: map[string][]string{"notifications": {"mailer"}},
+	})
+	billing := newTestModuleResult(t, "billing", DefaultServiceBusServiceName, AzureServiceBus{
+		Queues:        []string{"invoices", "orders"},
+		Topics:        []string{"notifications"},
+		Subscriptions: map[string][]string{"notifications": {"billing"}},
+	})
+	result, err := mergeProjectAnalysisResult(orders, billing)
+	require.NoError(t, err)
+	require.Equal(t, AzureServiceBus{
+		Queues:        []string{"orders", "invoices"},
+		Topics:        []string{"notifications"},
+		Subscriptions: map[string][]string{"notifications": {"mailer", "billing"}},
+	}, result.Services[DefaultServiceBusServiceName])
+	require.Equal(t, map[string]map[string]interface{}{
+		"orders":  {DefaultServiceBusServiceName: ""},
+		"billing": {DefaultServiceBusServiceName: ""},
+	}, result.ApplicationToBackingService)
+}
+
+func TestMergeProjectAnalysisResultWithEventHubs(t *testing.T) {
+	producer := newTestModuleResult(t, "producer", DefaultEventHubsServiceName, AzureEventHubs{
+		Hubs: []string{"telemetry"},
+	})
+	consumer := newTestModuleResult(t, "consumer", DefaultEventHubsServiceName, AzureEventHubs{
+		Hubs:           []string{"telemetry", "alerts"},
+		ConsumerGroups: map[string][]string{"telemetry": {"dashboard"}, "alerts": {"pager"}},
+	})
+	auditor := newTestModuleResult(t, "auditor", DefaultEventHubsServiceName, AzureEventHubs{
+		Hubs:           []string{"telemetry"},
+		ConsumerGroups: map[string][]string{"telemetry": {"audit", "dashboard"}},
+	})
+	result, err := mergeProjectAnalysisResult(producer, consumer)
+	require.NoError(t, err)
+	result, err = mergeProjectAnalysisResult(result, auditor)
+	require.NoError(t, err)
+	require.Equal(t, AzureEventHubs{
+		Hubs:           []string{"telemetry", "alerts"},
+		ConsumerGroups: map[string][]string{"telemetry": {"dashboard", "audit"}, "alerts": {"pager"}},
+	}, result.Services[DefaultEventHubsServiceName])
+	require.Len(t, result.ApplicationToBackingService, 3)
+}
+
+// newTestDatasourceModuleResult is the result of a module having an application using the datasource URL.
+func newTestDatasourceModuleResult(t *testing.T, applicationName string, url string) ProjectAnalysisResult {
+	result := ProjectAnalysisResult{Name: "project"}
+	require.NoError(t, addApplicationToResult(&result, applicationName, Application{
+		Properties: map[string]PropertyValue{
+			"spring.datasource.url": {Name: "spring.datasource.url", Value: url,
+				Origin: PropertyOrigin{FilePath: "application.properties", Line: 1}},
+		},
+	}))
+	require.NoError(t, addApplicationRelatedHostingServiceToResult(&result, applicationName, applicationName,
+		AzureContainerApp{}))
+	require.NoError(t, detectDatasources(detectionContext{result: &result, applicationName: applicationName,
+		properties: internal.NewTestPropertyValues(map[string]string{"spring.datasource.url": url})}))
+	return result
+}
+
+func TestMergeProjectAnalysisResultWithDifferentDatabases(t *testing.T) {
+	orders := newTestDatasourceModuleResult(t, "orders", "jdbc:postgresql://db:5432/orders")
+	inventory := newTestDatasourceModuleResult(t, "inventory", "jdbc:postgresql://db:5432/inventory")
+	reports := newTestDatasourceModuleResult(t, "reports", "jdbc:postgresql://db:5432/orders")
+	result, err := mergeProjectAnalysisResult(orders, inventory)
+	require.NoError(t, err)
+	result, err = mergeProjectAnalysisResult(result, reports)
+	require.NoError(t, err)
+	require.Equal(t, map[string]Service{
+		"orders":                     AzureContainerApp{},
+		"inventory":                  AzureContainerApp{},
+		"reports":                    AzureContainerApp{},
+		DefaultPostgresqlServiceName: AzureDatabaseForPostgresql{DatabaseName: "orders"},
+		"postgresql-inventory":       AzureDatabaseForPostgresql{DatabaseName: "inventory"},
+	}, result.Services)
+	require.Equal(t, map[string]map[string]interface{}{
+		"orders":    {DefaultPostgresqlServiceName: ""},
+		"inventory": {"postgresql-inventory": ""},
+		"reports":   {DefaultPostgresqlServiceName: ""},
+	}, result.ApplicationToBackingService)
+	inventoryApplication := result.Applications["inventory"]
+	require.Equal(t, "postgresql-inventory", inventoryApplication.Datasources[0].ServiceName)
+	require.Equal(t, []Evidence{
+		{Kind: EvidenceKindProperty, Value: "spring.datasource.url", FilePath: "application.properties", Line: 1},
+	}, inventoryApplication.ServiceEvidence["postgresql-inventory"])
+	require.NotContains(t, inventoryApplication.ServiceEvidence, DefaultPostgresqlServiceName)
+}
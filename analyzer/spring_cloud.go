@@ -0,0 +1,156 @@
+package analyzer
+
+import (
+	"sort"
+	"strings"
+
+	"ajpa/analyzer/internal"
+)
+
+// detectSpringCloudComponents detects the Spring Cloud roles of the application by its dependencies. An application
+// importing "configserver:" by spring.config.import is also a config client.
+func detectSpringCloudComponents(pom internal.Pom, properties internal.PropertyValues,
+	externalConfigSources []string) []SpringCloudComponent {
+	var result []SpringCloudComponent
+	if hasDependency(pom, "org.springframework.cloud", "spring-cloud-starter-gateway") ||
+		hasDependency(pom, "org.springframework.cloud", "spring-cloud-starter-gateway-mvc") ||
+		hasDependency(pom, "org.springframework.cloud", "spring-cloud-starter-gateway-server-webflux") ||
+		hasDependency(pom, "org.springframework.cloud", "spring-cloud-starter-gateway-server-webmvc") {
+		result = append(result, SpringCloudComponentGateway)
+	}
+	if hasDependency(pom, "org.springframework.cloud", "spring-cloud-starter-netflix-eureka-server") ||
+		hasDependency(pom, "org.springframework.cloud", "spring-cloud-netflix-eureka-server") {
+		result = append(result, SpringCloudComponentDiscoveryServer)
+	}
+	if (hasDependency(pom, "org.springframework.cloud", "spring-cloud-starter-netflix-eureka-client") ||
+		hasDependency(pom, "org.springframework.cloud", "spring-cloud-netflix-eureka-client")) &&
+		!isPropertyFalse(properties, "eureka.client.enabled") {
+		result = append(result, SpringCloudComponentDiscoveryClient)
+	}
+	if hasDependency(pom, "org.springframework.cloud", "spring-cloud-config-server") ||
+		hasDependency(pom, "org.springframework.cloud", "spring-cloud-starter-config-server") {
+		result = append(result, SpringCloudComponentConfigServer)
+	}
+	isConfigClient := hasDependency(pom, "org.springframework.cloud", "spring-cloud-starter-config") ||
+		hasDependency(pom, "org.springframework.cloud", "spring-cloud-config-client")
+	for _, source := range externalConfigSources {
+		isConfigClient = isConfigClient || strings.HasPrefix(source, "configserver:")
+	}
+	if isConfigClient && !isPropertyFalse(properties, "spring.cloud.config.enabled") {
+		result = append(result, SpringCloudComponentConfigClient)
+	}
+	return result
+}
+
+// getServiceReferences gets the other applications called by the application, from gateway routes, @FeignClient
+// annotations and URL property values.
+func getServiceReferences(properties internal.PropertyValues, annotations []internal.JavaAnnotation) []string {
+	result := internal.GetGatewayRouteServiceIds(properties)
+	for _, annotation := range annotations {
+		if annotation.Name != "FeignClient" {
+			continue
+		}
+		if urls := resolveAnnotationValues(annotation.Attributes["url"], properties); len(urls) > 0 {
+			// The name is only used as the client name if the url is set.
+			for _, url := range urls {
+				if host, ok := internal.GetUrlServiceHost(url); ok {
+					result = internal.AppendAndDistinctInOrder(result, host)
+				}
+			}
+			continue
+		}
+		for _, attributeName := range []string{"name", "value", "serviceId"} {
+			result = internal.AppendAndDistinctInOrder(result,
+				resolveAnnotationValues(annotation.Attributes[attributeName], properties)...)
+		}
+	}
+	return internal.AppendAndDistinctInOrder(result, internal.GetUrlServiceReferences(properties)...)
+}
+
+func isPropertyTrue(properties internal.PropertyValues, name string) bool {
+	value, ok := internal.GetPropertyValue(properties, name)
+	return ok && strings.EqualFold(strings.TrimSpace(value), "true")
+}
+
+func isPropertyFalse(properties internal.PropertyValues, name string) bool {
+	value, ok := internal.GetPropertyValue(properties, name)
+	return ok && strings.EqualFold(strings.TrimSpace(value), "false")
+}
+
+func hasSpringCloudComponent(application Application, component SpringCloudComponent) bool {
+	for _, c := range application.SpringCloudComponents {
+		if c == component {
+			return true
+		}
+	}
+	return false
+}
+
+// resolveApplicationTopology adds the edges between applications and sets the ingress of each application. It's
+// called after all applications are analyzed:
+//  1. ServiceReferences are resolved to applications by application name or spring.application.name, ignoring case
+//     like Eureka service ids.
+//  2. Discovery clients use the discovery servers, and config clients use the config servers.
+//  3. If any gateway exists, the gateways get external ingress and other web applications get internal ingress.
+//     Otherwise, all web applications get external ingress.
+func resolveApplicationTopology(result *ProjectAnalysisResult) error {
+	applicationNames := make([]string, 0, len(result.Applications))
+	for name := range result.Applications {
+		applicationNames = append(applicationNames, name)
+	}
+	sort.Strings(applicationNames)
+	serviceIdToApplicationName := make(map[string]string)
+	for _, name := range applicationNames {
+		serviceIdToApplicationName[strings.ToLower(name)] = name
+	}
+	for _, name := range applicationNames {
+		if serviceId := getSpringApplicationName(result.Applications[name]); serviceId != "" {
+			serviceIdToApplicationName[strings.ToLower(serviceId)] = name
+		}
+	}
+	hasGateway := false
+	for _, name := range applicationNames {
+		application := result.Applications[name]
+		hasGateway = hasGateway || hasSpringCloudComponent(application, SpringCloudComponentGateway)
+		var usedApplicationNames []string
+		for _, serviceId := range application.ServiceReferences {
+			if usedApplicationName, ok := serviceIdToApplicationName[strings.ToLower(serviceId)]; ok {
+				usedApplicationNames = append(usedApplicationNames, usedApplicationName)
+			}
+		}
+		for _, otherName := range applicationNames {
+			other := result.Applications[otherName]
+			if (hasSpringCloudComponent(application, SpringCloudComponentDiscoveryClient) &&
+				hasSpringCloudComponent(other, SpringCloudComponentDiscoveryServer)) ||
+				(hasSpringCloudComponent(application, SpringCloudComponentConfigClient) &&
+					hasSpringCloudComponent(other, SpringCloudComponentConfigServer)) {
+				usedApplicationNames = append(usedApplicationNames, otherName)
+			}
+		}
+		for _, usedApplicationName := range usedApplicationNames {
+			if usedApplicationName == name {
+				continue
+			}
+			if err := addApplicationToApplicationEdgeToResult(result, name, usedApplicationName); err != nil {
+				return err
+			}
+		}
+	}
+	for _, name := range applicationNames {
+		application := result.Applications[name]
+		switch {
+		case application.WebApplicationType == WebApplicationTypeNone:
+			application.Ingress = ""
+		case !hasGateway || hasSpringCloudComponent(application, SpringCloudComponentGateway):
+			application.Ingress = IngressTypeExternal
+		default:
+			application.Ingress = IngressTypeInternal
+		}
+		result.Applications[name] = application
+	}
+	return nil
+}
+
+func getSpringApplicationName(application Application) string {
+	return strings.TrimSpace(application.Properties[internal.CanonicalPropertyName("spring.application.name")].Value)
+}
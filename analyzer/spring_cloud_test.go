@@ -0,0 +1,141 @@
+package analyzer
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"ajpa/analyzer/internal"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDetectSpringCloudComponents(t *testing.T) {
+	pom, err := internal.ParseTestPom(`
+		<project>
+			<dependencies>
+				<dependency>
+					<groupId>org.springframework.cloud</groupId>
+					<artifactId>spring-cloud-starter-gateway</artifactId>
+				</dependency>
+				<dependency>
+					<groupId>org.springframework.cloud</groupId>
+					<artifactId>spring-cloud-starter-netflix-eureka-client</artifactId>
+				</dependency>
+			</dependencies>
+		</project>`)
+	require.NoError(t, err)
+	require.Equal(t,
+		[]SpringCloudComponent{
+			SpringCloudComponentGateway,
+			SpringCloudComponentDiscoveryClient,
+			SpringCloudComponentConfigClient,
+		},
+		detectSpringCloudComponents(pom, nil, []string{"configserver:http://config-server:8888"}))
+	require.Equal(t,
+		[]SpringCloudComponent{SpringCloudComponentGateway},
+		detectSpringCloudComponents(pom,
+			internal.NewTestPropertyValues(map[string]string{"eureka.client.enabled": "false"}), nil))
+}
+
+func TestResolveApplicationTopology(t *testing.T) {
+	gateway := newTestSpringCloudApplication(t, nil, []string{"configserver:http://config-server:8888"},
+		"org.springframework.cloud:spring-cloud-starter-gateway",
+		"org.springframework.cloud:spring-cloud-starter-netflix-eureka-client")
+	gateway.ServiceReferences = []string{"customers-service", "VETS-SERVICE", "unknown-service"}
+	result := ProjectAnalysisResult{
+		Applications: map[string]Application{
+			"api-gateway": gateway,
+			"customers": newTestSpringCloudApplication(t,
+				map[string]string{"spring.application.name": "customers-service"}, nil,
+				"org.springframework.boot:spring-boot-starter-web",
+				"org.springframework.cloud:spring-cloud-starter-netflix-eureka-client"),
+			"vets-service": newTestSpringCloudApplication(t, nil, nil,
+				"org.springframework.boot:spring-boot-starter-web",
+				"org.springframework.cloud:spring-cloud-starter-netflix-eureka-client",
+				"org.springframework.cloud:spring-cloud-starter-config"),
+			"discovery-server": newTestSpringCloudApplication(t, nil, nil,
+				"org.springframework.cloud:spring-cloud-starter-netflix-eureka-server",
+				"org.springframework.cloud:spring-cloud-starter-config"),
+			"config-server": newTestSpringCloudApplication(t, nil, nil,
+				"org.springframework.cloud:spring-cloud-config-server"),
+			"worker": newTestSpringCloudApplication(t, nil, nil, "org.springframework.boot:spring-boot-starter"),
+		},
+	}
+	require.NoError(t, resolveApplicationTopology(&result))
+	require.Equal(t, map[string]map[string]interface{}{
+		"api-gateway": {
+			"customers":        "",
+			"vets-service":     "",
+			"discovery-server": "",
+			"config-server":    "",
+		},
+		"customers": {
+			"discovery-server": "",
+		},
+		"vets-service": {
+			"discovery-server": "",
+			"config-server":    "",
+		},
+		"discovery-server": {
+			"config-server": "",
+		},
+	}, result.ApplicationToApplication)
+	require.Equal(t, IngressTypeExternal, result.Applications["api-gateway"].Ingress)
+	require.Equal(t, IngressTypeInternal, result.Applications["customers"].Ingress)
+	require.Equal(t, IngressTypeInternal, result.Applications["config-server"].Ingress)
+	require.Equal(t, IngressType(""), result.Applications["worker"].Ingress)
+}
+
+// newTestSpringCloudApplication detects the web application type and Spring Cloud components of the application having
+// the dependencies like "org.springframework.cloud:spring-cloud-config-server".
+func newTestSpringCloudApplication(t *testing.T, properties map[string]string, externalConfigSources []string,
+	dependencies ...string) Application {
+	var builder strings.Builder
+	builder.WriteString("<project><dependencies>")
+	for _, dependency := range dependencies {
+		groupId, artifactId, _ := strings.Cut(dependency, ":")
+		fmt.Fprintf(&builder, "<dependency><groupId>%s</groupId><artifactId>%s</artifactId></dependency>",
+			groupId, artifactId)
+	}
+	builder.WriteString("</dependencies></project>")
+	pom, err := internal.ParseTestPom(builder.String())
+	require.NoError(t, err)
+	values := internal.NewTestPropertyValues(properties)
+	return Application{
+		Properties:            newApplicationProperties("", values),
+		WebApplicationType:    detectWebApplicationType(pom, values, nil),
+		SpringCloudComponents: detectSpringCloudComponents(pom, values, externalConfigSources),
+	}
+}
+
+func TestResolveApplicationTopologyWithoutGateway(t *testing.T) {
+	result := ProjectAnalysisResult{
+		Applications: map[string]Application{
+			"web":    {WebApplicationType: WebApplicationTypeServlet},
+			"worker": {WebApplicationType: WebApplicationTypeNone},
+		},
+	}
+	require.NoError(t, resolveApplicationTopology(&result))
+	require.Nil(t, result.ApplicationToApplication)
+	require.Equal(t, IngressTypeExternal, result.Applications["web"].Ingress)
+	require.Equal(t, IngressType(""), result.Applications["worker"].Ingress)
+}
+
+func TestGetServiceReferences(t *testing.T) {
+	properties := internal.NewTestPropertyValues(map[string]string{
+		"spring.cloud.gateway.routes[0].uri": "lb://vets-service",
+		"app.visits-url":                     "http://visits-service:8082",
+		"app.customers":                      "customers-service",
+	})
+	annotations := []internal.JavaAnnotation{
+		{Name: "FeignClient", Attributes: map[string][]string{"name": {"${app.customers}"}}},
+		{Name: "FeignClient", Attributes: map[string][]string{"value": {"reviews-service"}}},
+		{Name: "FeignClient", Attributes: map[string][]string{"name": {"payments"},
+			"url": {"http://payments-service"}}},
+		{Name: "FeignClient", Attributes: map[string][]string{"name": {"github"}, "url": {"https://api.github.com"}}},
+	}
+	require.Equal(t,
+		[]string{"vets-service", "customers-service", "reviews-service", "payments-service", "visits-service"},
+		getServiceReferences(properties, annotations))
+}
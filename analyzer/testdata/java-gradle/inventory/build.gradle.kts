@@ -0,0 +1,12 @@
+plugins {
+    id("io.micronaut.application") version "4.3.4"
+}
+
+dependencies {
+    annotationProcessor("io.micronaut.data:micronaut-data-processor")
+    implementation("io.micronaut:micronaut-http-server-netty")
+    implementation("io.micronaut:micronaut-management")
+    implementation("io.micronaut.data:micronaut-data-jdbc")
+    implementation("io.micronaut.redis:micronaut-redis-lettuce")
+    runtimeOnly("com.mysql:mysql-connector-j")
+}
@@ -48,6 +48,8 @@ type ServiceConfig struct {
 	RelativePath string              `yaml:"project"`
 	Host         ServiceTargetKind   `yaml:"host"`
 	Language     ServiceLanguageKind `yaml:"language"`
+	// Env is used when building the service, like BP_JVM_VERSION used by Java buildpacks.
+	Env map[string]string `yaml:"env,omitempty"`
 }
 
 type ResourceType string
@@ -61,6 +63,8 @@ const (
 	ResourceTypeMessagingEventHubs  ResourceType = "messaging.eventhubs"
 	ResourceTypeMessagingServiceBus ResourceType = "messaging.servicebus"
 	ResourceTypeStorage             ResourceType = "storage"
+	ResourceTypeKeyVault            ResourceType = "keyvault"
+	ResourceTypeAppConfig           ResourceType = "appconfig"
 )
 
 type ResourceConfig struct {
@@ -173,8 +177,26 @@ func (r *ResourceConfig) UnmarshalYAML(value *yaml.Node) error {
 }
 
 type ContainerAppProps struct {
-	Port int             `yaml:"port,omitempty"`
-	Env  []ServiceEnvVar `yaml:"env,omitempty"`
+	Port int `yaml:"port,omitempty"`
+	// Internal limits the ingress to the Container Apps environment, so the app is only reachable from other apps.
+	Internal bool                `yaml:"internal,omitempty"`
+	Env      []ServiceEnvVar     `yaml:"env,omitempty"`
+	Probes   []ContainerAppProbe `yaml:"probes,omitempty"`
+}
+
+type ContainerAppProbeType string
+
+const (
+	ContainerAppProbeTypeLiveness  ContainerAppProbeType = "Liveness"
+	ContainerAppProbeTypeReadiness ContainerAppProbeType = "Readiness"
+	ContainerAppProbeTypeStartup   ContainerAppProbeType = "Startup"
+)
+
+// ContainerAppProbe is an HTTP GET probe of the container app.
+type ContainerAppProbe struct {
+	Type ContainerAppProbeType `yaml:"type"`
+	Path string                `yaml:"path"`
+	Port int                   `yaml:"port"`
 }
 
 type ServiceEnvVar struct {
@@ -197,10 +219,14 @@ type AIModelPropsModel struct {
 type ServiceBusProps struct {
 	Queues []string `yaml:"queues,omitempty"`
 	Topics []string `yaml:"topics,omitempty"`
+	// Subscriptions maps topic names to the names of their subscriptions.
+	Subscriptions map[string][]string `yaml:"subscriptions,omitempty"`
 }
 
 type EventHubsProps struct {
 	Hubs []string `yaml:"hubs,omitempty"`
+	// ConsumerGroups maps hub names to the names of their consumer groups, except the default consumer group.
+	ConsumerGroups map[string][]string `yaml:"consumerGroups,omitempty"`
 }
 
 type StorageProps struct {
@@ -2,6 +2,7 @@ package converter
 
 import (
 	"fmt"
+	"sort"
 
 	"ajpa/analyzer"
 	"ajpa/converter/azd"
@@ -16,12 +17,21 @@ func ProjectAnalysisResultToAzdProjectConfig(result analyzer.ProjectAnalysisResu
 		config.Services[name] = &azd.ServiceConfig{
 			Name:         name,
 			RelativePath: app.ProjectRelativePath,
-			Host:         azd.ContainerAppTarget, // todo: support other kinds.
+			Host:         toServiceHost(app),
 			Language:     azd.ServiceLanguageJava,
+			Env:          toServiceEnv(app),
 		}
 	}
 	config.Resources = make(map[string]*azd.ResourceConfig)
 	for name, service := range result.Services {
+		switch service.(type) {
+		case analyzer.AzureFunctionApp:
+			// azd has no resource type for function apps, they are provisioned for the services hosted by them.
+			continue
+		case analyzer.AzureSqlDatabase, analyzer.AzureDatabaseForMariadb:
+			// azd can't provision them, the analyzer adds a diagnostic that they need manual provisioning.
+			continue
+		}
 		resourceType, err := toResourceType(service)
 		if err != nil {
 			return azd.ProjectConfig{}, err
@@ -36,40 +46,119 @@ func ProjectAnalysisResultToAzdProjectConfig(result analyzer.ProjectAnalysisResu
 			Props: props,
 		}
 	}
+	for appName, app := range result.Applications {
+		if resource, ok := config.Resources[result.ApplicationToHostingService[appName]]; ok &&
+			resource.Type == azd.ResourceTypeHostContainerApp {
+			resource.Props = toContainerAppProps(app)
+		}
+	}
+	for _, appName := range sortedKeys(result.ApplicationToApplication) {
+		resource, ok := config.Resources[result.ApplicationToHostingService[appName]]
+		if !ok {
+			continue
+		}
+		for _, usedAppName := range sortedKeys(result.ApplicationToApplication[appName]) {
+			usedHostingName, ok := result.ApplicationToHostingService[usedAppName]
+			if !ok {
+				return azd.ProjectConfig{}, fmt.Errorf("hosting service of application %s doesn't exist", usedAppName)
+			}
+			if _, ok := config.Resources[usedHostingName]; ok {
+				resource.Uses = append(resource.Uses, usedHostingName)
+			}
+		}
+	}
 	for appName, serviceNameMap := range result.ApplicationToBackingService {
-		hostingName := result.ApplicationToHostingService[appName]
+		resource, ok := config.Resources[result.ApplicationToHostingService[appName]]
+		if !ok {
+			continue
+		}
 		for serviceName := range serviceNameMap {
-			config.Resources[hostingName].Uses = append(config.Resources[hostingName].Uses, serviceName)
+			if _, ok := config.Resources[serviceName]; ok {
+				resource.Uses = append(resource.Uses, serviceName)
+			}
 		}
 	}
 	return config, nil
 }
 
+// toServiceHost hosts Azure Functions applications by function apps, and other applications by container apps.
+func toServiceHost(app analyzer.Application) azd.ServiceTargetKind {
+	if app.Framework == analyzer.FrameworkAzureFunctions {
+		return azd.AzureFunctionTarget
+	}
+	return azd.ContainerAppTarget // todo: support other kinds.
+}
+
+// toServiceEnv sets BP_JVM_VERSION, so Java buildpacks use the JDK of the application's Java version.
+func toServiceEnv(app analyzer.Application) map[string]string {
+	if app.JavaVersion == "" {
+		return nil
+	}
+	return map[string]string{"BP_JVM_VERSION": app.JavaVersion}
+}
+
+const defaultContainerAppPort = 8080
+
+// toContainerAppProps sets the port of the container app to the application's HTTP port. The port is omitted for
+// applications which don't serve HTTP, so no ingress is created for them. The ingress is internal for applications
+// only called by other applications, like the ones behind a Spring Cloud Gateway. Probes are added if the
+// application serves health endpoints.
+func toContainerAppProps(app analyzer.Application) azd.ContainerAppProps {
+	var props azd.ContainerAppProps
+	switch {
+	case app.WebApplicationType == analyzer.WebApplicationTypeNone:
+	case app.Port > 0:
+		props.Port = app.Port
+	default:
+		props.Port = defaultContainerAppPort
+	}
+	props.Internal = props.Port > 0 && app.Ingress == analyzer.IngressTypeInternal
+	if probes := app.HealthProbes; probes != nil {
+		props.Probes = []azd.ContainerAppProbe{
+			{Type: azd.ContainerAppProbeTypeLiveness, Path: probes.LivenessPath, Port: probes.Port},
+			{Type: azd.ContainerAppProbeTypeReadiness, Path: probes.ReadinessPath, Port: probes.Port},
+			{Type: azd.ContainerAppProbeTypeStartup, Path: probes.StartupPath, Port: probes.Port},
+		}
+	}
+	return props
+}
+
 func toProps(service analyzer.Service) (interface{}, error) {
 	switch s := service.(type) {
 	case analyzer.AzureContainerApp:
 		return azd.ContainerAppProps{
-			Port: 8080, // todo: support non-web app.
+			Port: defaultContainerAppPort,
 		}, nil
 	case analyzer.AzureDatabaseForPostgresql, // todo: Add database name in PostgresqlProps
 		analyzer.AzureDatabaseForMysql,
 		analyzer.AzureCacheForRedis,
 		analyzer.AzureCosmosDbForMongoDb,
-		analyzer.AzureCosmosDb:
+		analyzer.AzureCosmosDb,
+		analyzer.AzureKeyVault,
+		analyzer.AzureAppConfiguration:
 		return nil, nil
 	case analyzer.AzureServiceBus:
 		return azd.ServiceBusProps{
-			Queues: s.Queues,
-			Topics: s.Topics,
+			Queues:        s.Queues,
+			Topics:        s.Topics,
+			Subscriptions: s.Subscriptions,
 		}, nil
 	case analyzer.AzureEventHubs:
 		return azd.EventHubsProps{
-			Hubs: s.Hubs,
+			Hubs:           s.Hubs,
+			ConsumerGroups: s.ConsumerGroups,
 		}, nil
 	case analyzer.AzureStorageAccount:
 		return azd.StorageProps{
 			Containers: s.Containers,
 		}, nil
+	case analyzer.AzureOpenAiModel:
+		return azd.AIModelProps{
+			Model: azd.AIModelPropsModel{
+				Name:    s.Name,
+				Version: s.Version,
+			},
+		}, nil
 	default:
 		return "", fmt.Errorf("unknown service type when get Props: %v", service)
 	}
@@ -95,7 +184,22 @@ func toResourceType(service analyzer.Service) (azd.ResourceType, error) {
 		return azd.ResourceTypeMessagingEventHubs, nil
 	case analyzer.AzureStorageAccount:
 		return azd.ResourceTypeStorage, nil
+	case analyzer.AzureKeyVault:
+		return azd.ResourceTypeKeyVault, nil
+	case analyzer.AzureOpenAiModel:
+		return azd.ResourceTypeOpenAiModel, nil
+	case analyzer.AzureAppConfiguration:
+		return azd.ResourceTypeAppConfig, nil
 	default:
 		return "", fmt.Errorf("unknown service type: %v", service)
 	}
 }
+
+func sortedKeys[V any](m map[string]V) []string {
+	result := make([]string, 0, len(m))
+	for key := range m {
+		result = append(result, key)
+	}
+	sort.Strings(result)
+	return result
+}
@@ -61,6 +61,304 @@ func TestProjectAnalysisResultToAzdProjectConfig(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "sql server and mariadb need manual provisioning",
+			result: analyzer.ProjectAnalysisResult{
+				Name: "app-one-sample",
+				Applications: map[string]analyzer.Application{
+					"app-one": {ProjectRelativePath: "app-one"},
+				},
+				Services: map[string]analyzer.Service{
+					"app-one":                            analyzer.AzureContainerApp{},
+					analyzer.DefaultSqlServerServiceName: analyzer.AzureSqlDatabase{DatabaseName: "orders"},
+					analyzer.DefaultMariadbServiceName:   analyzer.AzureDatabaseForMariadb{DatabaseName: "inventory"},
+				},
+				ApplicationToHostingService: map[string]string{
+					"app-one": "app-one",
+				},
+				ApplicationToBackingService: map[string]map[string]interface{}{
+					"app-one": {
+						analyzer.DefaultSqlServerServiceName: "",
+						analyzer.DefaultMariadbServiceName:   "",
+					},
+				},
+			},
+			expected: azd.ProjectConfig{
+				Name: "app-one-sample",
+				Services: map[string]*azd.ServiceConfig{
+					"app-one": {
+						Name:         "app-one",
+						Language:     azd.ServiceLanguageJava,
+						RelativePath: "app-one",
+						Host:         azd.ContainerAppTarget,
+					},
+				},
+				Resources: map[string]*azd.ResourceConfig{
+					"app-one": {
+						Type:  azd.ResourceTypeHostContainerApp,
+						Name:  "app-one",
+						Props: azd.ContainerAppProps{Port: 8080},
+					},
+				},
+			},
+		},
+		{
+			name: "openai models",
+			result: analyzer.ProjectAnalysisResult{
+				Name: "chat-sample",
+				Applications: map[string]analyzer.Application{
+					"chat": {ProjectRelativePath: "chat"},
+				},
+				Services: map[string]analyzer.Service{
+					"chat":                   analyzer.AzureContainerApp{},
+					"gpt-4o":                 analyzer.AzureOpenAiModel{Name: "gpt-4o", Version: "2024-08-06"},
+					"text-embedding-3-small": analyzer.AzureOpenAiModel{Name: "text-embedding-3-small", Version: "1"},
+				},
+				ApplicationToHostingService: map[string]string{
+					"chat": "chat",
+				},
+				ApplicationToBackingService: map[string]map[string]interface{}{
+					"chat": {
+						"gpt-4o": "",
+					},
+				},
+			},
+			expected: azd.ProjectConfig{
+				Name: "chat-sample",
+				Services: map[string]*azd.ServiceConfig{
+					"chat": {
+						Name:         "chat",
+						Language:     azd.ServiceLanguageJava,
+						RelativePath: "chat",
+						Host:         azd.ContainerAppTarget,
+					},
+				},
+				Resources: map[string]*azd.ResourceConfig{
+					"chat": {
+						Type:  azd.ResourceTypeHostContainerApp,
+						Name:  "chat",
+						Uses:  []string{"gpt-4o"},
+						Props: azd.ContainerAppProps{Port: 8080},
+					},
+					"gpt-4o": {
+						Type: azd.ResourceTypeOpenAiModel,
+						Name: "gpt-4o",
+						Props: azd.AIModelProps{
+							Model: azd.AIModelPropsModel{Name: "gpt-4o", Version: "2024-08-06"},
+						},
+					},
+					"text-embedding-3-small": {
+						Type: azd.ResourceTypeOpenAiModel,
+						Name: "text-embedding-3-small",
+						Props: azd.AIModelProps{
+							Model: azd.AIModelPropsModel{Name: "text-embedding-3-small", Version: "1"},
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "web application port, probes and worker without ingress",
+			result: analyzer.ProjectAnalysisResult{
+				Name: "web-and-worker-sample",
+				Applications: map[string]analyzer.Application{
+					"web": {
+						ProjectRelativePath: "web",
+						WebApplicationType:  analyzer.WebApplicationTypeServlet,
+						Port:                8081,
+						JavaVersion:         "21",
+						HealthProbes: &analyzer.HealthProbes{
+							Port:          8081,
+							LivenessPath:  "/actuator/health/liveness",
+							ReadinessPath: "/actuator/health/readiness",
+							StartupPath:   "/actuator/health/liveness",
+						},
+					},
+					"worker": {
+						ProjectRelativePath: "worker",
+						WebApplicationType:  analyzer.WebApplicationTypeNone,
+					},
+				},
+				Services: map[string]analyzer.Service{
+					"web":    analyzer.AzureContainerApp{},
+					"worker": analyzer.AzureContainerApp{},
+				},
+				ApplicationToHostingService: map[string]string{
+					"web":    "web",
+					"worker": "worker",
+				},
+			},
+			expected: azd.ProjectConfig{
+				Name: "web-and-worker-sample",
+				Services: map[string]*azd.ServiceConfig{
+					"web": {
+						Name:         "web",
+						Language:     azd.ServiceLanguageJava,
+						RelativePath: "web",
+						Host:         azd.ContainerAppTarget,
+						Env:          map[string]string{"BP_JVM_VERSION": "21"},
+					},
+					"worker": {
+						Name:         "worker",
+						Language:     azd.ServiceLanguageJava,
+						RelativePath: "worker",
+						Host:         azd.ContainerAppTarget,
+					},
+				},
+				Resources: map[string]*azd.ResourceConfig{
+					"web": {
+						Type: azd.ResourceTypeHostContainerApp,
+						Name: "web",
+						Props: azd.ContainerAppProps{
+							Port: 8081,
+							Probes: []azd.ContainerAppProbe{
+								{Type: azd.ContainerAppProbeTypeLiveness, Path: "/actuator/health/liveness", Port: 8081},
+								{Type: azd.ContainerAppProbeTypeReadiness, Path: "/actuator/health/readiness", Port: 8081},
+								{Type: azd.ContainerAppProbeTypeStartup, Path: "/actuator/health/liveness", Port: 8081},
+							},
+						},
+					},
+					"worker": {
+						Type:  azd.ResourceTypeHostContainerApp,
+						Name:  "worker",
+						Props: azd.ContainerAppProps{},
+					},
+				},
+			},
+		},
+		{
+			name: "application uses application",
+			result: analyzer.ProjectAnalysisResult{
+				Name: "gateway-sample",
+				Applications: map[string]analyzer.Application{
+					"api-gateway": {ProjectRelativePath: "api-gateway", Ingress: analyzer.IngressTypeExternal},
+					"customers":   {ProjectRelativePath: "customers", Ingress: analyzer.IngressTypeInternal},
+				},
+				Services: map[string]analyzer.Service{
+					"api-gateway": analyzer.AzureContainerApp{},
+					"customers":   analyzer.AzureContainerApp{},
+				},
+				ApplicationToHostingService: map[string]string{
+					"api-gateway": "api-gateway",
+					"customers":   "customers",
+				},
+				ApplicationToApplication: map[string]map[string]interface{}{
+					"api-gateway": {
+						"customers": "",
+					},
+				},
+			},
+			expected: azd.ProjectConfig{
+				Name: "gateway-sample",
+				Services: map[string]*azd.ServiceConfig{
+					"api-gateway": {
+						Name:         "api-gateway",
+						Language:     azd.ServiceLanguageJava,
+						RelativePath: "api-gateway",
+						Host:         azd.ContainerAppTarget,
+					},
+					"customers": {
+						Name:         "customers",
+						Language:     azd.ServiceLanguageJava,
+						RelativePath: "customers",
+						Host:         azd.ContainerAppTarget,
+					},
+				},
+				Resources: map[string]*azd.ResourceConfig{
+					"api-gateway": {
+						Type:  azd.ResourceTypeHostContainerApp,
+						Name:  "api-gateway",
+						Uses:  []string{"customers"},
+						Props: azd.ContainerAppProps{Port: 8080},
+					},
+					"customers": {
+						Type:  azd.ResourceTypeHostContainerApp,
+						Name:  "customers",
+						Props: azd.ContainerAppProps{Port: 8080, Internal: true},
+					},
+				},
+			},
+		},
+		{
+			name: "azure functions application",
+			result: analyzer.ProjectAnalysisResult{
+				Name: "functions-sample",
+				Applications: map[string]analyzer.Application{
+					"order-functions": {
+						ProjectRelativePath: "order-functions",
+						Framework:           analyzer.FrameworkAzureFunctions,
+						WebApplicationType:  analyzer.WebApplicationTypeNone,
+					},
+				},
+				Services: map[string]analyzer.Service{
+					"order-functions": analyzer.AzureFunctionApp{},
+					analyzer.DefaultServiceBusServiceName: analyzer.AzureServiceBus{
+						Queues:        []string{"orders"},
+						Topics:        []string{"notifications"},
+						Subscriptions: map[string][]string{"notifications": {"mailer"}},
+					},
+					analyzer.DefaultEventHubsServiceName: analyzer.AzureEventHubs{
+						Hubs:           []string{"telemetry"},
+						ConsumerGroups: map[string][]string{"telemetry": {"alerts"}},
+					},
+					analyzer.DefaultKeyVaultServiceName: analyzer.AzureKeyVault{
+						Endpoints: []string{"https://orders.vault.azure.net/"},
+					},
+					analyzer.DefaultAppConfigurationServiceName: analyzer.AzureAppConfiguration{
+						Stores: []analyzer.AzureAppConfigurationStore{{Endpoint: "https://orders.azconfig.io"}},
+					},
+				},
+				ApplicationToHostingService: map[string]string{
+					"order-functions": "order-functions",
+				},
+				ApplicationToBackingService: map[string]map[string]interface{}{
+					"order-functions": {
+						analyzer.DefaultServiceBusServiceName:       "",
+						analyzer.DefaultEventHubsServiceName:        "",
+						analyzer.DefaultKeyVaultServiceName:         "",
+						analyzer.DefaultAppConfigurationServiceName: "",
+					},
+				},
+			},
+			expected: azd.ProjectConfig{
+				Name: "functions-sample",
+				Services: map[string]*azd.ServiceConfig{
+					"order-functions": {
+						Name:         "order-functions",
+						Language:     azd.ServiceLanguageJava,
+						RelativePath: "order-functions",
+						Host:         azd.AzureFunctionTarget,
+					},
+				},
+				Resources: map[string]*azd.ResourceConfig{
+					analyzer.DefaultServiceBusServiceName: {
+						Type: azd.ResourceTypeMessagingServiceBus,
+						Name: analyzer.DefaultServiceBusServiceName,
+						Props: azd.ServiceBusProps{
+							Queues:        []string{"orders"},
+							Topics:        []string{"notifications"},
+							Subscriptions: map[string][]string{"notifications": {"mailer"}},
+						},
+					},
+					analyzer.DefaultEventHubsServiceName: {
+						Type: azd.ResourceTypeMessagingEventHubs,
+						Name: analyzer.DefaultEventHubsServiceName,
+						Props: azd.EventHubsProps{
+							Hubs:           []string{"telemetry"},
+							ConsumerGroups: map[string][]string{"telemetry": {"alerts"}},
+						},
+					},
+					analyzer.DefaultKeyVaultServiceName: {
+						Type: azd.ResourceTypeKeyVault,
+						Name: analyzer.DefaultKeyVaultServiceName,
+					},
+					analyzer.DefaultAppConfigurationServiceName: {
+						Type: azd.ResourceTypeAppConfig,
+						Name: analyzer.DefaultAppConfigurationServiceName,
+					},
+				},
+			},
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -9,6 +9,7 @@ import (
 	"ajpa/analyzer"
 	"ajpa/converter"
 	"ajpa/converter/azd"
+	"ajpa/report"
 )
 
 func main() {
@@ -18,17 +19,36 @@ func main() {
 		return
 	}
 	cwd := flag.String("cwd", dir, "change working directory")
+	skipMalformedConfig := flag.Bool("skip-malformed-config", false,
+		"skip config files which can not be parsed and report them as warnings, instead of failing")
+	configRepo := flag.String("config-repo", "",
+		"local directory or git checkout used by Spring Cloud Config Server, its properties are merged into "+
+			"the properties of each application")
+	deploymentConfig := flag.Bool("deployment-config", false,
+		"read .env files and Kubernetes ConfigMaps and Deployments in k8s/*.yaml as the lowest precedence properties")
+	reportPath := flag.String("report", "",
+		"write a Markdown report of the applications, services and resolved properties with their origins to the file")
+	detectionRules := flag.String("detection-rules", "",
+		"YAML file of rules detecting backing services, which replace the built-in rules having the same id")
 	// todo: add other flags like:
 	// 1. output dir.
 	// 2. output to console.
 	// 3. log level.
 	flag.Parse()
 
-	result, err := analyzer.AnalyzeJavaProject(*cwd)
+	result, err := analyzer.AnalyzeJavaProjectWithOptions(*cwd, analyzer.AnalyzeOptions{
+		SkipMalformedConfig:  *skipMalformedConfig,
+		ConfigRepositoryPath: *configRepo,
+		ReadDeploymentConfig: *deploymentConfig,
+		DetectionRulesPath:   *detectionRules,
+	})
 	if err != nil {
 		fmt.Println(err)
 		return
 	}
+	for _, diagnostic := range result.Diagnostics {
+		fmt.Println(diagnostic)
+	}
 	config, err := converter.ProjectAnalysisResultToAzdProjectConfig(result)
 	if err != nil {
 		fmt.Println(err)
@@ -41,4 +61,12 @@ func main() {
 		return
 	}
 	fmt.Println("File generated: ", path)
+	if *reportPath != "" {
+		err = report.Save(result, *reportPath)
+		if err != nil {
+			fmt.Println(err)
+			return
+		}
+		fmt.Println("File generated: ", *reportPath)
+	}
 }